@@ -0,0 +1,37 @@
+// Package asset embeds the published snapshot segment link lists.
+package asset
+
+import (
+	_ "embed"
+	"strings"
+)
+
+var (
+	//go:embed bc-mainnet-snapshot-segment-links.txt
+	mainnetSegmentLinks string
+
+	//go:embed bc-testnet-snapshot-segment-links.txt
+	testnetSegmentLinks string
+)
+
+// MainnetSegmentLinks returns the ordered segment links of the mainnet snapshot.
+func MainnetSegmentLinks() []string {
+	return parseLinks(mainnetSegmentLinks)
+}
+
+// TestnetSegmentLinks returns the ordered segment links of the testnet snapshot.
+func TestnetSegmentLinks() []string {
+	return parseLinks(testnetSegmentLinks)
+}
+
+func parseLinks(data string) []string {
+	links := make([]string, 0)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		links = append(links, line)
+	}
+	return links
+}
@@ -0,0 +1,126 @@
+package claim
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/cosmos/go-bip39"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/hd"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// messagePrefix separates the hash of a claim from the hashes signed for other
+// purposes.
+const messagePrefix = "\x19BNB Beacon Chain Claim:\n"
+
+// Message is the claim of a balance by the owner of its Beacon Chain address,
+// the recipient receives the balance on BNB Smart Chain.
+type Message struct {
+	Address   sdk.AccAddress `json:"address"`
+	Denom     string         `json:"denom"`
+	Amount    int64          `json:"amount"`
+	Root      common.Hash    `json:"root"`
+	Recipient common.Address `json:"recipient"`
+}
+
+// Hash returns the signed hash of the message: keccak256 of the prefix, the 20
+// bytes address, the 32 bytes symbol, the 32 bytes amount, the root and the
+// 20 bytes recipient.
+func (m *Message) Hash() []byte {
+	symbol := Symbol(m.Denom)
+	return crypto.Keccak256(
+		[]byte(messagePrefix),
+		m.Address.Bytes(),
+		symbol[:],
+		big.NewInt(m.Amount).FillBytes(make([]byte, 32)),
+		m.Root.Bytes(),
+		m.Recipient.Bytes(),
+	)
+}
+
+// SignedMessage is a message signed by the owner of its Beacon Chain address.
+// The public key is compressed and the signature is the 65 bytes recoverable
+// signature of the message hash.
+type SignedMessage struct {
+	Message
+	PubKey    hexutil.Bytes `json:"pub_key"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Sign signs the message with a secp256k1 Beacon Chain private key, the message
+// address must be the address of the key.
+func Sign(message *Message, privKey []byte) (*SignedMessage, error) {
+	key, err := crypto.ToECDSA(privKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := crypto.CompressPubkey(&key.PublicKey)
+	if addr := Address(pubKey); !addr.Equals(message.Address) {
+		return nil, fmt.Errorf("key of %s can not sign the claim of %s", addr.String(), message.Address.String())
+	}
+	signature, err := crypto.Sign(message.Hash(), key)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedMessage{
+		Message:   *message,
+		PubKey:    pubKey,
+		Signature: signature,
+	}, nil
+}
+
+// Verify checks that the signature is made by the public key, and that the
+// public key derives the address of the message.
+func Verify(signed *SignedMessage) error {
+	if len(signed.PubKey) != secp256k1.PubKeySize {
+		return fmt.Errorf("public key should be %d bytes compressed secp256k1", secp256k1.PubKeySize)
+	}
+	if len(signed.Signature) != crypto.SignatureLength {
+		return fmt.Errorf("signature should be %d bytes", crypto.SignatureLength)
+	}
+	r := new(big.Int).SetBytes(signed.Signature[:32])
+	s := new(big.Int).SetBytes(signed.Signature[32:64])
+	if !crypto.ValidateSignatureValues(signed.Signature[64], r, s, true) {
+		return fmt.Errorf("invalid signature values")
+	}
+
+	recovered, err := crypto.SigToPub(signed.Hash(), signed.Signature)
+	if err != nil {
+		return fmt.Errorf("recover public key: %w", err)
+	}
+	if !bytes.Equal(crypto.CompressPubkey(recovered), signed.PubKey) {
+		return fmt.Errorf("signature is not made by public key %s", signed.PubKey.String())
+	}
+	if addr := Address(signed.PubKey); !addr.Equals(signed.Address) {
+		return fmt.Errorf("public key of %s does not own %s", addr.String(), signed.Address.String())
+	}
+	return nil
+}
+
+// Address returns the Beacon Chain address of a compressed secp256k1 public
+// key, RIPEMD160(SHA256(pubkey)).
+func Address(pubKey []byte) sdk.AccAddress {
+	return sdk.AccAddress(secp256k1.PubKeySecp256k1(pubKey).Address())
+}
+
+// PrivKeyFromMnemonic derives the secp256k1 private key of the Beacon Chain
+// account at m/44'/714'/account'/0/index.
+func PrivKeyFromMnemonic(mnemonic string, passphrase string, account uint32, index uint32) ([]byte, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	master, chainCode := hd.ComputeMastersFromSeed(seed)
+	privKey, err := hd.DerivePrivateKeyForPath(master, chainCode, hd.NewFundraiserParams(account, index).String())
+	if err != nil {
+		return nil, err
+	}
+	return privKey[:], nil
+}
@@ -0,0 +1,110 @@
+package claim
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func testMessage(t *testing.T, privKey []byte) *Message {
+	return &Message{
+		Address:   sdk.AccAddress(secp256k1.PrivKeySecp256k1(privKey).PubKey().Address()),
+		Denom:     "BUSD-BD1",
+		Amount:    2500000000,
+		Root:      common.HexToHash("0x086161bae6254bcf2d99de4dcce3b4136d91b04e847f0922e224a307c42de9a6"),
+		Recipient: common.HexToAddress("0x1111111111111111111111111111111111111111"),
+	}
+}
+
+func TestPrivKeyFromMnemonic(t *testing.T) {
+	privKey, err := PrivKeyFromMnemonic(testMnemonic, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := PrivKeyFromMnemonic(testMnemonic, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.Bytes2Hex(privKey) != common.Bytes2Hex(again) {
+		t.Error("derivation is not deterministic")
+	}
+	other, err := PrivKeyFromMnemonic(testMnemonic, "", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.Bytes2Hex(privKey) == common.Bytes2Hex(other) {
+		t.Error("index is not part of the derivation")
+	}
+	if _, err := PrivKeyFromMnemonic("abandon abandon", "", 0, 0); err == nil {
+		t.Error("expected invalid mnemonic")
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	privKey, err := PrivKeyFromMnemonic(testMnemonic, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := testMessage(t, privKey)
+	signed, err := Sign(message, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(signed); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	// the public key derives the address the same way as the Beacon Chain
+	pubKey := secp256k1.PrivKeySecp256k1(privKey).PubKey()
+	if !Address(signed.PubKey).Equals(sdk.AccAddress(pubKey.Address())) {
+		t.Error("address derivation mismatch")
+	}
+
+	tampered := []func(m *SignedMessage){
+		func(m *SignedMessage) { m.Amount++ },
+		func(m *SignedMessage) { m.Denom = "BNB" },
+		func(m *SignedMessage) { m.Root[0] ^= 0xff },
+		func(m *SignedMessage) { m.Recipient[0] ^= 0xff },
+		func(m *SignedMessage) { m.Signature[10] ^= 0xff },
+		func(m *SignedMessage) { m.Address = sdk.AccAddress(make([]byte, 20)) },
+	}
+	for i, tamper := range tampered {
+		copied := *signed
+		copied.Signature = append([]byte{}, signed.Signature...)
+		copied.Address = append(sdk.AccAddress{}, signed.Address...)
+		tamper(&copied)
+		if err := Verify(&copied); err == nil {
+			t.Errorf("tampered claim %d is verified", i)
+		}
+	}
+}
+
+func TestSignOtherAddress(t *testing.T) {
+	privKey, err := PrivKeyFromMnemonic(testMnemonic, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := PrivKeyFromMnemonic(testMnemonic, "", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sign(testMessage(t, other), privKey); err == nil {
+		t.Error("expected the key to be rejected for another address")
+	}
+
+	// a valid signature of another key does not prove the ownership
+	signed, err := Sign(testMessage(t, other), other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed.Address = testMessage(t, privKey).Address
+	if err := Verify(signed); err == nil {
+		t.Error("expected ownership mismatch")
+	}
+}
@@ -0,0 +1,90 @@
+// Package claim builds the transactions that recover the exported balances on
+// BNB Smart Chain.
+package claim
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bnb-chain/node-dump/proof"
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// RecoverABI is the ABI of the recovery call. ownerPubKey is the compressed
+// secp256k1 public key of the Beacon Chain address and ownerSignature its
+// signature of the claim.
+const RecoverABI = `[{"type":"function","name":"recover","stateMutability":"nonpayable",
+"inputs":[{"name":"tokenSymbol","type":"bytes32"},{"name":"amount","type":"uint256"},
+{"name":"ownerPubKey","type":"bytes"},{"name":"ownerSignature","type":"bytes"},
+{"name":"merkleProof","type":"bytes32[]"}],"outputs":[]}]`
+
+var recoverABI abi.ABI
+
+func init() {
+	var err error
+	recoverABI, err = abi.JSON(strings.NewReader(RecoverABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Symbol returns the symbol of denom right padded to 32 bytes, as in the leaf
+// encoding.
+func Symbol(denom string) [proof.SymbolLength]byte {
+	var symbol [proof.SymbolLength]byte
+	copy(symbol[:], denom)
+	return symbol
+}
+
+// PackRecover returns the calldata of the recovery call of the exported proof.
+func PackRecover(exportedProof *types.ExportedProof, ownerPubKey []byte, ownerSignature []byte) ([]byte, error) {
+	siblings, err := proof.DecodeProof(exportedProof.Proof)
+	if err != nil {
+		return nil, err
+	}
+	merkleProof := make([][32]byte, 0, len(siblings))
+	for _, sibling := range siblings {
+		if len(sibling) != 32 {
+			return nil, fmt.Errorf("proof element %x is not 32 bytes", sibling)
+		}
+		merkleProof = append(merkleProof, common.BytesToHash(sibling))
+	}
+	return recoverABI.Pack("recover",
+		Symbol(exportedProof.Coin.Denom),
+		big.NewInt(exportedProof.Coin.Amount),
+		ownerPubKey,
+		ownerSignature,
+		merkleProof,
+	)
+}
+
+// TxParams are the parameters of a recovery transaction.
+type TxParams struct {
+	ChainID   *big.Int
+	Contract  common.Address
+	Nonce     uint64
+	Gas       uint64
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// NewRecoverTx returns the unsigned EIP-1559 transaction calling the recovery
+// contract with data.
+func NewRecoverTx(params *TxParams, data []byte) *ethtypes.Transaction {
+	contract := params.Contract
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:   params.ChainID,
+		Nonce:     params.Nonce,
+		GasTipCap: params.GasTipCap,
+		GasFeeCap: params.GasFeeCap,
+		Gas:       params.Gas,
+		To:        &contract,
+		Value:     big.NewInt(0),
+		Data:      data,
+	})
+}
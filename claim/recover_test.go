@@ -0,0 +1,95 @@
+package claim
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node-dump/proof"
+)
+
+func TestPackRecover(t *testing.T) {
+	leaves := []*proof.Leaf{
+		proof.NewLeaf(sdk.AccAddress(bytes.Repeat([]byte{0x01}, 20)), sdk.NewCoin("BNB", 100000000)),
+		proof.NewLeaf(sdk.AccAddress(bytes.Repeat([]byte{0x02}, 20)), sdk.NewCoin("BUSD-BD1", 2500000000)),
+		proof.NewLeaf(sdk.AccAddress(bytes.Repeat([]byte{0x03}, 20)), sdk.NewCoin("BNB", 1)),
+	}
+	tree, err := proof.NewTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported := tree.ExportedProof(1)
+	pubKey := bytes.Repeat([]byte{0x02}, 33)
+	signature := bytes.Repeat([]byte{0xaa}, 65)
+
+	data, err := PackRecover(exported, pubKey, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := recoverABI.Methods["recover"]
+	if !bytes.Equal(data[:4], method.ID) {
+		t.Fatalf("unexpected selector %x", data[:4])
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if symbol := args[0].([32]byte); symbol != Symbol("BUSD-BD1") {
+		t.Errorf("unexpected symbol %x", symbol)
+	}
+	if amount := args[1].(*big.Int); amount.Int64() != 2500000000 {
+		t.Errorf("unexpected amount %s", amount)
+	}
+	if !bytes.Equal(args[2].([]byte), pubKey) || !bytes.Equal(args[3].([]byte), signature) {
+		t.Error("unexpected owner public key or signature")
+	}
+	merkleProof := args[4].([][32]byte)
+	siblings := tree.Proof(1)
+	if len(merkleProof) != len(siblings) {
+		t.Fatalf("expected %d proof elements, got %d", len(siblings), len(merkleProof))
+	}
+	for i := range siblings {
+		if !bytes.Equal(merkleProof[i][:], siblings[i]) {
+			t.Errorf("proof element %d mismatch", i)
+		}
+	}
+}
+
+func TestNewRecoverTx(t *testing.T) {
+	params := &TxParams{
+		ChainID:   big.NewInt(56),
+		Contract:  common.HexToAddress("0x0000000000000000000000000000000000003000"),
+		Nonce:     7,
+		Gas:       500000,
+		GasTipCap: big.NewInt(1000000000),
+		GasFeeCap: big.NewInt(5000000000),
+	}
+	tx := NewRecoverTx(params, []byte{0x01, 0x02})
+	if tx.Type() != ethtypes.DynamicFeeTxType {
+		t.Fatalf("expected an EIP-1559 transaction, got type %d", tx.Type())
+	}
+	if *tx.To() != params.Contract || tx.Nonce() != 7 || tx.Gas() != 500000 || tx.ChainId().Int64() != 56 {
+		t.Error("unexpected transaction fields")
+	}
+	if tx.Value().Sign() != 0 {
+		t.Error("recovery transaction should not transfer value")
+	}
+
+	// the unsigned transaction is decoded by an offline signer
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ethtypes.Transaction
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash() != tx.Hash() {
+		t.Error("decoded transaction mismatch")
+	}
+}
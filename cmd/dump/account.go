@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmCrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/multisig"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+	nodetypes "github.com/bnb-chain/node/common/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// exportPubKey returns the exported public key of an account, it is nil for an
+// account that has never signed a transaction. The key is the raw key for
+// secp256k1 and ed25519, and the amino encoding otherwise.
+func exportPubKey(pubKey tmCrypto.PubKey) *types.ExportedPubKey {
+	switch key := pubKey.(type) {
+	case nil:
+		return nil
+	case secp256k1.PubKeySecp256k1:
+		return &types.ExportedPubKey{Type: "secp256k1", Key: key}
+	case ed25519.PubKeyEd25519:
+		return &types.ExportedPubKey{Type: "ed25519", Key: key[:]}
+	case multisig.PubKeyMultisigThreshold:
+		return &types.ExportedPubKey{Type: "multisig", Key: key.Bytes()}
+	default:
+		return &types.ExportedPubKey{Type: fmt.Sprintf("%T", key), Key: key.Bytes()}
+	}
+}
+
+// accountBalances returns the available coins of an account, the coins frozen
+// by the owner, the coins locked in open orders and their sorted total.
+func accountBalances(acc nodetypes.NamedAccount) (available, frozen, locked, total sdk.Coins) {
+	available = acc.GetCoins()
+	frozen = acc.GetFrozenCoins()
+	locked = acc.GetLockedCoins()
+	total = available.Plus(frozen).Plus(locked).Sort()
+	return available, frozen, locked, total
+}
+
+// exportAccount returns the exported account of acc, its coins are the total
+// of its balances.
+func exportAccount(acc nodetypes.NamedAccount) *types.ExportedAccount {
+	available, frozen, locked, total := accountBalances(acc)
+	return &types.ExportedAccount{
+		Address:       acc.GetAddress(),
+		AccountNumber: acc.GetAccountNumber(),
+		Sequence:      acc.GetSequence(),
+		PubKey:        exportPubKey(acc.GetPubKey()),
+		Coins:         total,
+		Available:     available,
+		Frozen:        frozen,
+		Locked:        locked,
+	}
+}
+
+// summarizeAccounts counts the holders of a nonzero balance with and without
+// a public key, and sums their frozen and locked coins.
+func summarizeAccounts(accounts []*types.ExportedAccount) *types.AccountSummary {
+	summary := &types.AccountSummary{
+		Frozen: sdk.Coins{},
+		Locked: sdk.Coins{},
+	}
+	for _, account := range accounts {
+		if account.Coins.IsZero() {
+			continue
+		}
+		summary.Holders++
+		summary.Frozen = summary.Frozen.Plus(account.Frozen)
+		summary.Locked = summary.Locked.Plus(account.Locked)
+		if account.PubKey != nil {
+			summary.HoldersWithPubKey++
+		} else {
+			summary.HoldersWithoutPubKey++
+		}
+	}
+	return summary
+}
+
+// AccountDetailOf returns the account of addr at the last block height.
+func AccountDetailOf(app *app.BNBBeaconChain, addr sdk.AccAddress) (*types.AccountDetail, error) {
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	acc := app.AccountKeeper.GetAccount(ctx, addr)
+	if acc == nil {
+		return nil, fmt.Errorf("account %s does not exist", addr.String())
+	}
+	namedAcc := acc.(nodetypes.NamedAccount)
+	return &types.AccountDetail{
+		ChainID:         app.CheckState.Ctx.ChainID(),
+		BlockHeight:     app.LastBlockHeight(),
+		ExportedAccount: *exportAccount(namedAcc),
+		Name:            namedAcc.GetName(),
+		Flags:           namedAcc.GetFlags(),
+	}, nil
+}
+
+// AccountCmd prints an account of the app.
+func AccountCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account <bech32|hex>",
+		Short: "Print an account at the last block height",
+		Long: `Print an account at the last block height in JSON.
+
+The account is printed with its account number, sequence, public key, name and
+flags, and its available, frozen and locked coins, Coins being their total.
+With --proofs, the proofs of its balances in the export are printed with the
+state root of the export, which should be of the chain ID and height of the app.
+The application DB is opened read only.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("<bech32|hex> should be set")
+			}
+			addr, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			detail, err := AccountDetailOf(dapp, addr)
+			if err != nil {
+				return err
+			}
+
+			if proofPath := viper.GetString(flagProofs); proofPath != "" {
+				state, proofs, err := findAccountProofs(proofPath, addr)
+				if err != nil {
+					return err
+				}
+				if state.ChainID != detail.ChainID || state.BlockHeight != detail.BlockHeight {
+					return fmt.Errorf("export of %s at height %d is not of the app of %s at height %d",
+						state.ChainID, state.BlockHeight, detail.ChainID, detail.BlockHeight)
+				}
+				detail.ProofRoot = state.StateRoot
+				detail.Proofs = proofs
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "\t")
+			return encoder.Encode(detail)
+		},
+	}
+	cmd.Flags().String(flagProofs, "", "directory of the export containing base.json and proofs.json")
+	return cmd
+}
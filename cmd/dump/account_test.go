@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	nodetypes "github.com/bnb-chain/node/common/types"
+
+	"github.com/bnb-chain/node-dump/proof"
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// newTestAccount returns an account of addr holding coins in its three
+// balances.
+func newTestAccount(addr sdk.AccAddress, available, frozen, locked sdk.Coins) *nodetypes.AppAccount {
+	return &nodetypes.AppAccount{
+		BaseAccount: auth.BaseAccount{Address: addr, Coins: available, AccountNumber: 7, Sequence: 3},
+		FrozenCoins: frozen,
+		LockedCoins: locked,
+	}
+}
+
+func TestExportAccount(t *testing.T) {
+	acc := newTestAccount(testAddress(1),
+		sdk.Coins{{Denom: "BNB", Amount: 100}, {Denom: "XYZ-000", Amount: 5}},
+		sdk.Coins{{Denom: "BNB", Amount: 20}, {Denom: "ABC-000", Amount: 7}},
+		sdk.Coins{{Denom: "BNB", Amount: 3}})
+	pubKey := secp256k1.GenPrivKey().PubKey().(secp256k1.PubKeySecp256k1)
+	acc.SetPubKey(pubKey)
+
+	exported := exportAccount(acc)
+	if !exported.Address.Equals(acc.Address) || exported.AccountNumber != 7 || exported.Sequence != 3 {
+		t.Errorf("unexpected account %+v", exported)
+	}
+	if !exported.Available.IsEqual(acc.Coins) || !exported.Frozen.IsEqual(acc.FrozenCoins) || !exported.Locked.IsEqual(acc.LockedCoins) {
+		t.Errorf("unexpected balances available %v frozen %v locked %v", exported.Available, exported.Frozen, exported.Locked)
+	}
+	total := sdk.Coins{{Denom: "ABC-000", Amount: 7}, {Denom: "BNB", Amount: 123}, {Denom: "XYZ-000", Amount: 5}}
+	if !exported.Coins.IsEqual(total) {
+		t.Errorf("coins %v, want %v", exported.Coins, total)
+	}
+	if sum := exported.Available.Plus(exported.Frozen).Plus(exported.Locked).Sort(); !exported.Coins.IsEqual(sum) {
+		t.Errorf("coins %v are not the sum %v of the balances", exported.Coins, sum)
+	}
+	if exported.PubKey == nil || exported.PubKey.Type != "secp256k1" || !bytes.Equal(exported.PubKey.Key, pubKey[:]) {
+		t.Errorf("unexpected public key %+v", exported.PubKey)
+	}
+
+	// the leaves are those of the total before the balances were exported
+	var leaves []*proof.Leaf
+	for _, coin := range exported.Coins {
+		leaves = append(leaves, proof.NewLeaf(exported.Address, coin))
+	}
+	tree, err := proof.NewTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := proof.NewTree([]*proof.Leaf{
+		proof.NewLeaf(acc.Address, sdk.Coin{Denom: "ABC-000", Amount: 7}),
+		proof.NewLeaf(acc.Address, sdk.Coin{Denom: "BNB", Amount: 123}),
+		proof.NewLeaf(acc.Address, sdk.Coin{Denom: "XYZ-000", Amount: 5}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.RootHex() != expected.RootHex() {
+		t.Errorf("root %s, want %s", tree.RootHex(), expected.RootHex())
+	}
+}
+
+func TestExportPubKey(t *testing.T) {
+	if exported := exportPubKey(nil); exported != nil {
+		t.Errorf("public key of an account without key %+v", exported)
+	}
+
+	secp := secp256k1.GenPrivKey().PubKey().(secp256k1.PubKeySecp256k1)
+	if exported := exportPubKey(secp); exported == nil || exported.Type != "secp256k1" || !bytes.Equal(exported.Key, secp[:]) {
+		t.Errorf("unexpected secp256k1 public key %+v", exported)
+	}
+
+	ed := ed25519.GenPrivKey().PubKey().(ed25519.PubKeyEd25519)
+	if exported := exportPubKey(ed); exported == nil || exported.Type != "ed25519" || !bytes.Equal(exported.Key, ed[:]) {
+		t.Errorf("unexpected ed25519 public key %+v", exported)
+	}
+}
+
+func TestSummarizeAccounts(t *testing.T) {
+	withKey := newTestAccount(testAddress(1), sdk.Coins{{Denom: "BNB", Amount: 1}}, sdk.Coins{{Denom: "BNB", Amount: 2}}, nil)
+	withKey.SetPubKey(secp256k1.GenPrivKey().PubKey())
+	// locked coins alone make a holder
+	withoutKey := newTestAccount(testAddress(2), nil, nil, sdk.Coins{{Denom: "BNB", Amount: 4}})
+	empty := newTestAccount(testAddress(3), nil, nil, nil)
+	empty.SetPubKey(secp256k1.GenPrivKey().PubKey())
+
+	summary := summarizeAccounts([]*types.ExportedAccount{exportAccount(withKey), exportAccount(withoutKey), exportAccount(empty)})
+	if summary.Holders != 2 || summary.HoldersWithPubKey != 1 || summary.HoldersWithoutPubKey != 1 {
+		t.Errorf("unexpected holders %+v", summary)
+	}
+	if !summary.Frozen.IsEqual(sdk.Coins{{Denom: "BNB", Amount: 2}}) || !summary.Locked.IsEqual(sdk.Coins{{Denom: "BNB", Amount: 4}}) {
+		t.Errorf("unexpected frozen %v and locked %v", summary.Frozen, summary.Locked)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/bnb-chain/node/app"
+	nodetypes "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/utils"
+	tkclient "github.com/bnb-chain/node/plugins/tokens/client/rest"
+)
+
+const (
+	flagListenAddr = "laddr"
+
+	apiPrefix          = "/api/v1"
+	maxTokensLimit     = 1000
+	defaultTokensLimit = 100
+)
+
+// apiServer serves the read only endpoints of the Beacon Chain REST API from
+// the ABCI queries of the app. The queries are serialized, as Tendermint does
+// for the ABCI connection of a node.
+type apiServer struct {
+	mu  sync.Mutex
+	app *app.BNBBeaconChain
+}
+
+func (s *apiServer) query(path string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := s.app.Query(abci.RequestQuery{Path: path})
+	if !res.IsOK() {
+		return nil, errors.New(res.Log)
+	}
+	return res.Value, nil
+}
+
+func (s *apiServer) router() *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc(apiPrefix+"/account/{address}", s.handleAccount).Methods("GET")
+	r.HandleFunc(apiPrefix+"/tokens", s.handleTokens(false)).Methods("GET")
+	r.HandleFunc(apiPrefix+"/mini/tokens", s.handleTokens(true)).Methods("GET")
+	return r
+}
+
+func throw(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(message))
+}
+
+func writeJSONResponse(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// handleAccount serves the account with its balances by symbol, an unknown
+// account is not found.
+func (s *apiServer) handleAccount(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		auth.BaseAccount
+		Flags    uint64                  `json:"flags"`
+		Balances []tkclient.TokenBalance `json:"balances"`
+		Coins    *struct{}               `json:"coins,omitempty"` // omit `coins`
+	}
+
+	address := mux.Vars(r)["address"]
+	if _, err := sdk.AccAddressFromBech32(address); err != nil {
+		throw(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := s.query(fmt.Sprintf("/account/%s", address))
+	if err != nil {
+		throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't query account. Error: %s", err.Error()))
+		return
+	}
+	if len(res) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var acc sdk.Account
+	if err := app.Codec.UnmarshalBinaryBare(res, &acc); err != nil {
+		throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't parse query result. Error: %s", err.Error()))
+		return
+	}
+	appAcc, ok := acc.(*nodetypes.AppAccount)
+	if !ok {
+		throw(w, http.StatusInternalServerError, fmt.Sprintf("unexpected account type %T", acc))
+		return
+	}
+
+	data, err := json.Marshal(response{
+		BaseAccount: appAcc.BaseAccount,
+		Flags:       appAcc.Flags,
+		Balances:    tokenBalances(appAcc),
+	})
+	if err != nil {
+		throw(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSONResponse(w, append(data, '\n'))
+}
+
+// tokenBalances returns the free, locked and frozen balances of an account by
+// symbol.
+func tokenBalances(acc nodetypes.NamedAccount) []tkclient.TokenBalance {
+	balances := make(map[string]*tkclient.TokenBalance)
+	balance := func(denom string) *tkclient.TokenBalance {
+		if _, ok := balances[denom]; !ok {
+			balances[denom] = &tkclient.TokenBalance{Symbol: denom}
+		}
+		return balances[denom]
+	}
+	for _, coin := range acc.GetCoins() {
+		balance(coin.Denom).Free = utils.Fixed8(coin.Amount)
+	}
+	for _, coin := range acc.GetLockedCoins() {
+		balance(coin.Denom).Locked = utils.Fixed8(coin.Amount)
+	}
+	for _, coin := range acc.GetFrozenCoins() {
+		balance(coin.Denom).Frozen = utils.Fixed8(coin.Amount)
+	}
+
+	res := make([]tkclient.TokenBalance, 0, len(balances))
+	for _, b := range balances {
+		res = append(res, *b)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Symbol < res[j].Symbol
+	})
+	return res
+}
+
+// handleTokens serves a page of the BEP2 or of the mini tokens, with the limit,
+// offset and showZeroSupplyTokens parameters of the REST API.
+func (s *apiServer) handleTokens(isMini bool) http.HandlerFunc {
+	queryPrefix := "tokens"
+	if isMini {
+		queryPrefix = "mini-tokens"
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultTokensLimit
+		if limitStr := r.FormValue("limit"); limitStr != "" && len(limitStr) < 100 {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, "invalid limit")
+				return
+			}
+			limit = parsed
+		}
+		if limit > maxTokensLimit {
+			limit = maxTokensLimit
+		}
+		offset := 0
+		if offsetStr := r.FormValue("offset"); offsetStr != "" && len(offsetStr) < 100 {
+			parsed, err := strconv.Atoi(offsetStr)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, "invalid offset")
+				return
+			}
+			offset = parsed
+		}
+		showZeroSupplyTokens := strings.ToLower(r.FormValue("showZeroSupplyTokens")) == "true"
+
+		res, err := s.query(fmt.Sprintf("%s/list/%d/%d/%s", queryPrefix, offset, limit, strconv.FormatBool(showZeroSupplyTokens)))
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		// the tokens are encoded as their concrete types
+		var tokens interface{} = &[]*nodetypes.Token{}
+		if isMini {
+			tokens = &[]*nodetypes.MiniToken{}
+		}
+		if err := app.Codec.UnmarshalBinaryLengthPrefixed(res, tokens); err != nil {
+			throw(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		data, err := app.Codec.MarshalJSON(tokens)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSONResponse(w, data)
+	}
+}
+
+// APIServerCmd serves the read only endpoints of the REST API.
+func APIServerCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-server",
+		Short: "Serve the read only endpoints of the Beacon Chain REST API from the archive",
+		Long: `Serve the read only endpoints of the Beacon Chain REST API from the application
+state of --home at the last block height. The application DB is opened read
+only.
+
+The endpoints /api/v1/account/{address}, /api/v1/tokens and /api/v1/mini/tokens
+answer with the responses of the REST API of the Beacon Chain, from the ABCI
+queries of the app.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			s := &apiServer{app: dapp}
+			addr := viper.GetString(flagListenAddr)
+			fmt.Println("API server listening on", addr, "height:", dapp.LastBlockHeight())
+			return http.ListenAndServe(addr, s.router())
+		},
+	}
+	cmd.Flags().String(flagListenAddr, "localhost:8080", "address to listen on")
+	return cmd
+}
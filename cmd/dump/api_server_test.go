@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// newTestAPIServer serves the API of a test archive holding the account of addr.
+func newTestAPIServer(t *testing.T, addr sdk.AccAddress) *httptest.Server {
+	newTestArchive(t, t.TempDir(), addr)
+	dapp, db, err := openReadOnlyApp(server.NewDefaultContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ts := httptest.NewServer((&apiServer{app: dapp}).router())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// getAPI returns the status and the body of the response to a GET of path.
+func getAPI(t *testing.T, ts *httptest.Server, path string) (int, []byte) {
+	resp, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestAPIServerAccount(t *testing.T) {
+	addr := testAddress(1)
+	ts := newTestAPIServer(t, addr)
+
+	status, body := getAPI(t, ts, apiPrefix+"/account/"+addr.String())
+	if status != http.StatusOK {
+		t.Fatalf("status %d: %s", status, body)
+	}
+	var account map[string]json.RawMessage
+	if err := json.Unmarshal(body, &account); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"address", "account_number", "sequence", "flags", "balances"} {
+		if _, exist := account[field]; !exist {
+			t.Errorf("no %s in %s", field, body)
+		}
+	}
+	if _, exist := account["coins"]; exist {
+		t.Errorf("coins in %s", body)
+	}
+	var balances []map[string]string
+	if err := json.Unmarshal(account["balances"], &balances); err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]string{
+		{"symbol": "ABC-000", "free": "100.00000000", "frozen": "0.00000000", "locked": "0.00000000"},
+		{"symbol": "BNB", "free": "100000000.00000000", "frozen": "0.00000000", "locked": "0.00000000"},
+		{"symbol": "XYZ-000", "free": "200.00000000", "frozen": "0.00000000", "locked": "0.00000000"},
+	}
+	if len(balances) != len(expected) {
+		t.Fatalf("balances %v, want %v", balances, expected)
+	}
+	for i, balance := range balances {
+		if len(balance) != len(expected[i]) {
+			t.Errorf("balance %v, want %v", balance, expected[i])
+		}
+		for field, value := range expected[i] {
+			if balance[field] != value {
+				t.Errorf("balance %v, want %v", balance, expected[i])
+			}
+		}
+	}
+
+	if status, _ := getAPI(t, ts, apiPrefix+"/account/"+testAddress(2).String()); status != http.StatusNotFound {
+		t.Errorf("status %d for an unknown account, want %d", status, http.StatusNotFound)
+	}
+	if status, _ := getAPI(t, ts, apiPrefix+"/account/invalid"); status != http.StatusBadRequest {
+		t.Errorf("status %d for an invalid address, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestAPIServerTokens(t *testing.T) {
+	ts := newTestAPIServer(t, testAddress(1))
+
+	for _, test := range []struct {
+		query   string
+		symbols []string
+	}{
+		{query: "", symbols: []string{"ABC-000", "BNB", "XYZ-000"}},
+		{query: "?limit=2", symbols: []string{"ABC-000", "BNB"}},
+		{query: "?offset=1", symbols: []string{"BNB", "XYZ-000"}},
+		{query: "?offset=1&limit=1", symbols: []string{"BNB"}},
+	} {
+		status, body := getAPI(t, ts, apiPrefix+"/tokens"+test.query)
+		if status != http.StatusOK {
+			t.Errorf("%s: status %d: %s", test.query, status, body)
+			continue
+		}
+		var tokens []struct {
+			Symbol string `json:"symbol"`
+		}
+		if err := json.Unmarshal(body, &tokens); err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+		symbols := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			symbols = append(symbols, token.Symbol)
+		}
+		if len(symbols) != len(test.symbols) {
+			t.Errorf("%s: tokens %v, want %v", test.query, symbols, test.symbols)
+			continue
+		}
+		for i := range symbols {
+			if symbols[i] != test.symbols[i] {
+				t.Errorf("%s: tokens %v, want %v", test.query, symbols, test.symbols)
+				break
+			}
+		}
+	}
+
+	if status, _ := getAPI(t, ts, apiPrefix+"/tokens?limit=many"); status != http.StatusExpectationFailed {
+		t.Errorf("status %d for an invalid limit, want %d", status, http.StatusExpectationFailed)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+	tmstate "github.com/tendermint/tendermint/state"
+	tmstore "github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagFrom = "from"
+	flagTo   = "to"
+)
+
+// blockReader reads the blocks and the validator sets of the block store and
+// the state DB of Tendermint.
+type blockReader struct {
+	blockDB    dbm.DB
+	stateDB    dbm.DB
+	blockStore *tmstore.BlockStore
+}
+
+func openBlockReader(home string) (*blockReader, error) {
+	blockDB, err := openReadOnlyDB(home, "blockstore")
+	if err != nil {
+		return nil, fmt.Errorf("open block store: %w", err)
+	}
+	stateDB, err := openReadOnlyDB(home, "state")
+	if err != nil {
+		blockDB.Close()
+		return nil, fmt.Errorf("open state: %w", err)
+	}
+	return &blockReader{
+		blockDB:    blockDB,
+		stateDB:    stateDB,
+		blockStore: tmstore.NewBlockStore(blockDB),
+	}, nil
+}
+
+func (r *blockReader) Close() {
+	r.blockDB.Close()
+	r.stateDB.Close()
+}
+
+// Commit returns the commit of the block of height. The commit of a block is
+// in the next block, the commit seen by the node is used for the last block of
+// the store.
+func (r *blockReader) Commit(height int64) (*tmtypes.Commit, error) {
+	commit := r.blockStore.LoadBlockCommit(height)
+	if commit == nil {
+		commit = r.blockStore.LoadSeenCommit(height)
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("commit of block %d is not in the block store", height)
+	}
+	return commit, nil
+}
+
+// Validators returns the validator set of height.
+func (r *blockReader) Validators(height int64) (*tmtypes.ValidatorSet, error) {
+	validators, err := tmstate.LoadValidators(r.stateDB, height)
+	if err != nil {
+		return nil, fmt.Errorf("validators of block %d: %w", height, err)
+	}
+	return validators, nil
+}
+
+// Block exports the header of height, its commit and the validator set that
+// signed it.
+func (r *blockReader) Block(height int64) (*types.ExportedBlock, error) {
+	meta := r.blockStore.LoadBlockMeta(height)
+	if meta == nil {
+		return nil, fmt.Errorf("block %d is not in the block store", height)
+	}
+	commit, err := r.Commit(height)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := r.Validators(height)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := &types.ExportedBlock{
+		Height: height,
+		Hash:   meta.BlockID.Hash,
+	}
+	if exported.Header, err = app.Codec.MarshalJSON(meta.Header); err != nil {
+		return nil, err
+	}
+	if exported.Commit, err = app.Codec.MarshalJSON(commit); err != nil {
+		return nil, err
+	}
+	if exported.Validators, err = app.Codec.MarshalJSON(validators); err != nil {
+		return nil, err
+	}
+	return exported, nil
+}
+
+// CheckAppHash compares the hash of the last commit of the app with the app
+// hash of its height recorded by Tendermint. The app hash of a height is in
+// the header of the next height, or in the state DB when that header is not
+// in the block store yet.
+func (r *blockReader) CheckAppHash(commitID sdk.CommitID) (*types.AppHashCheck, error) {
+	check := &types.AppHashCheck{
+		Height:     commitID.Version,
+		CommitHash: commitID.Hash,
+	}
+	if meta := r.blockStore.LoadBlockMeta(commitID.Version + 1); meta != nil {
+		check.Source = "header"
+		check.HeaderHeight = meta.Header.Height
+		check.AppHash = meta.Header.AppHash
+	} else {
+		state := tmstate.LoadState(r.stateDB)
+		if state.LastBlockHeight != commitID.Version {
+			return nil, fmt.Errorf("state height %d does not match the app height %d", state.LastBlockHeight, commitID.Version)
+		}
+		check.Source = "state"
+		check.AppHash = state.AppHash
+	}
+	check.Match = bytes.Equal(check.AppHash, check.CommitHash)
+	return check, nil
+}
+
+// ExportBlocksCmd exports the headers, commits and validator sets of a range
+// of blocks.
+func ExportBlocksCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-blocks <path>",
+		Short: "Export the headers, commits and validator sets of the block store",
+		Long: `Export the headers, commits and validator sets of the blocks from --from to
+--to of the Tendermint block store.
+
+The application DB, the block store and the state DB of --home are opened read
+only. Each block is exported with its header, the commit with the signatures of
+the validators and the validator set at its height, --to is the last block of
+the store when not set and --from is --to when not set. The hash of the last commit of the app is
+cross-checked with the app hash recorded by Tendermint for its height. The
+blocks are written to <path>/blocks.json.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<path> should be set")
+			}
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			reader, err := openBlockReader(viper.GetString("home"))
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+
+			to := viper.GetInt64(flagTo)
+			if to == 0 {
+				to = reader.blockStore.Height()
+			}
+			from := viper.GetInt64(flagFrom)
+			if from == 0 {
+				from = to
+			}
+			if from < 1 || from > to || to > reader.blockStore.Height() {
+				return fmt.Errorf("blocks %d to %d are not in the block store of height %d", from, to, reader.blockStore.Height())
+			}
+
+			exported := &types.ExportedBlocks{
+				ChainID: dapp.CheckState.Ctx.ChainID(),
+				From:    from,
+				To:      to,
+				Blocks:  make([]*types.ExportedBlock, 0, to-from+1),
+			}
+			for height := from; height <= to; height++ {
+				block, err := reader.Block(height)
+				if err != nil {
+					return err
+				}
+				exported.Blocks = append(exported.Blocks, block)
+			}
+			trace("blocks", from, "to", to)
+			check, err := reader.CheckAppHash(dapp.LastCommitID())
+			if err != nil {
+				return err
+			}
+			exported.AppHashCheck = *check
+
+			if err := os.MkdirAll(args[0], 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(path.Join(args[0], "blocks.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			err = writeJSONFile(file, exported)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Blocks exported:", len(exported.Blocks), "from:", from, "to:", to)
+
+			if !check.Match {
+				return fmt.Errorf("app hash %X of height %d does not match the commit hash %X", check.AppHash, check.Height, check.CommitHash)
+			}
+			fmt.Println("App hash of height", check.Height, "matches the commit hash")
+
+			return nil
+		},
+	}
+	cmd.Flags().Int64(flagFrom, 0, "first block height, --to when not set")
+	cmd.Flags().Int64(flagTo, 0, "last block height, the height of the block store when not set")
+	return cmd
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common"
+
+	"github.com/bnb-chain/node-dump/proof"
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagResume             = "resume"
+	flagCheckpointInterval = "checkpoint-interval"
+
+	checkpointDirName = ".checkpoint"
+	checkpointFile    = "checkpoint.json"
+	spillAccountsFile = "accounts.jsonl"
+	spillLeavesFile   = "leaves.jsonl"
+)
+
+var accountKeyPrefix = []byte("account:")
+
+// checkpointState is the progress of the account iteration of an export. The
+// spilled files are only valid up to the recorded sizes.
+type checkpointState struct {
+	ChainID      string                   `json:"chain_id"`
+	BlockHeight  int64                    `json:"block_height"`
+	CommitID     sdk.CommitID             `json:"commit_id"`
+	Excluded     []*types.ExcludedAccount `json:"excluded"`
+	LastKey      []byte                   `json:"last_key"`
+	Accounts     int64                    `json:"accounts"`
+	Leaves       int64                    `json:"leaves"`
+	AccountsSize int64                    `json:"accounts_size"`
+	LeavesSize   int64                    `json:"leaves_size"`
+}
+
+// exportCheckpoint spills the iterated accounts and leaves of an export to
+// disk and periodically records the last iterated account key, so that an
+// interrupted export can continue the iteration from that key.
+type exportCheckpoint struct {
+	dir      string
+	interval time.Duration
+	saved    time.Time
+	state    checkpointState
+
+	accountFile   *os.File
+	leafFile      *os.File
+	accountWriter *bufio.Writer
+	leafWriter    *bufio.Writer
+}
+
+// openExportCheckpoint opens the checkpoint in dir. When resume is set, the
+// accounts and leaves spilled before the last checkpoint are returned.
+func openExportCheckpoint(dir string, interval time.Duration, resume bool, chainID string, height int64, commitID sdk.CommitID,
+	excluded []*types.ExcludedAccount) (
+	*exportCheckpoint, []*types.ExportedAccount, []*proof.Leaf, error) {
+	checkpoint := &exportCheckpoint{
+		dir:      dir,
+		interval: interval,
+		saved:    time.Now(),
+		state: checkpointState{
+			ChainID:     chainID,
+			BlockHeight: height,
+			CommitID:    commitID,
+			Excluded:    excluded,
+		},
+	}
+	accounts := []*types.ExportedAccount{}
+	leaves := []*proof.Leaf{}
+
+	if resume {
+		data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("no checkpoint to resume: %w", err)
+		}
+		var state checkpointState
+		err = json.Unmarshal(data, &state)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if state.ChainID != chainID || state.BlockHeight != height || !bytes.Equal(state.CommitID.Hash, commitID.Hash) {
+			return nil, nil, nil, fmt.Errorf("checkpoint of %s at height %d does not match the database of %s at height %d",
+				state.ChainID, state.BlockHeight, chainID, height)
+		}
+		if !sameExcludedAccounts(state.Excluded, excluded) {
+			return nil, nil, nil, fmt.Errorf("checkpoint excludes different accounts, resume with the same --%s", flagExcludeFile)
+		}
+		checkpoint.state = state
+
+		accounts, err = loadSpill(filepath.Join(dir, spillAccountsFile), state.AccountsSize, func() *types.ExportedAccount {
+			return &types.ExportedAccount{}
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		leaves, err = loadSpill(filepath.Join(dir, spillLeavesFile), state.LeavesSize, func() *proof.Leaf {
+			return &proof.Leaf{}
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if int64(len(accounts)) != state.Accounts || int64(len(leaves)) != state.Leaves {
+			return nil, nil, nil, fmt.Errorf("checkpoint is corrupted: %d accounts and %d leaves spilled, expected %d and %d",
+				len(accounts), len(leaves), state.Accounts, state.Leaves)
+		}
+	} else {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, nil, err
+	}
+	var err error
+	checkpoint.accountFile, err = openSpill(filepath.Join(dir, spillAccountsFile), checkpoint.state.AccountsSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	checkpoint.leafFile, err = openSpill(filepath.Join(dir, spillLeavesFile), checkpoint.state.LeavesSize)
+	if err != nil {
+		checkpoint.accountFile.Close()
+		return nil, nil, nil, err
+	}
+	checkpoint.accountWriter = bufio.NewWriter(checkpoint.accountFile)
+	checkpoint.leafWriter = bufio.NewWriter(checkpoint.leafFile)
+
+	return checkpoint, accounts, leaves, nil
+}
+
+// LastKey returns the store key of the last account iterated before the
+// checkpoint, it is nil for a fresh export.
+func (c *exportCheckpoint) LastKey() []byte {
+	return c.state.LastKey
+}
+
+// Append spills an iterated account and its leaves, account is nil for a
+// skipped account. A checkpoint is saved when the interval has passed.
+func (c *exportCheckpoint) Append(key []byte, account *types.ExportedAccount, leaves []*proof.Leaf) error {
+	if account != nil {
+		n, err := writeJSONLine(c.accountWriter, account)
+		if err != nil {
+			return err
+		}
+		c.state.AccountsSize += n
+		c.state.Accounts++
+	}
+	for _, leaf := range leaves {
+		n, err := writeJSONLine(c.leafWriter, leaf)
+		if err != nil {
+			return err
+		}
+		c.state.LeavesSize += n
+		c.state.Leaves++
+	}
+	c.state.LastKey = append(c.state.LastKey[:0], key...)
+
+	if time.Since(c.saved) >= c.interval {
+		return c.Save()
+	}
+	return nil
+}
+
+// Save flushes the spilled data and records the checkpoint.
+func (c *exportCheckpoint) Save() error {
+	for _, w := range []*bufio.Writer{c.accountWriter, c.leafWriter} {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	for _, f := range []*os.File{c.accountFile, c.leafFile} {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(c.state)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(c.dir, checkpointFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(c.dir, checkpointFile)); err != nil {
+		return err
+	}
+	c.saved = time.Now()
+	trace("checkpoint saved", "accounts", c.state.Accounts, "leaves", c.state.Leaves)
+	return nil
+}
+
+// Close closes the spilled files.
+func (c *exportCheckpoint) Close() error {
+	err := c.accountFile.Close()
+	if leafErr := c.leafFile.Close(); err == nil {
+		err = leafErr
+	}
+	return err
+}
+
+// iterateAccountsFrom iterates the accounts in the order of
+// `AccountKeeper.IterateAccounts`, starting after the given store key.
+func iterateAccountsFrom(ctx sdk.Context, dapp *app.BNBBeaconChain, after []byte, process func(key []byte, acc sdk.Account) (stop bool)) error {
+	store := ctx.KVStore(common.AccountStoreKey)
+	start := accountKeyPrefix
+	if after != nil {
+		start = append(append([]byte{}, after...), 0)
+	}
+	iter := store.Iterator(start, sdk.PrefixEndBytes(accountKeyPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var acc sdk.Account
+		if err := dapp.Codec.UnmarshalBinaryBare(iter.Value(), &acc); err != nil {
+			return err
+		}
+		if process(iter.Key(), acc) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func sameExcludedAccounts(a, b []*types.ExcludedAccount) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Address.Equals(b[i].Address) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSONLine(w *bufio.Writer, v any) (int64, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+	data = append(data, '\n')
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// openSpill opens a spilled file for appending, dropping the data written
+// after the last checkpoint.
+func openSpill(name string, size int64) (*os.File, error) {
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func loadSpill[T any](name string, size int64, builder func() *T) ([]*T, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	items := make([]*T, 0)
+	decoder := json.NewDecoder(bufio.NewReader(io.LimitReader(file, size)))
+	for decoder.More() {
+		item := builder()
+		if err := decoder.Decode(item); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", name, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/viper"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common"
+	nodetypes "github.com/bnb-chain/node/common/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// newTestApp returns an app whose account store holds n accounts, some of
+// them without a nonzero coin.
+func newTestApp(t *testing.T, n int) *app.BNBBeaconChain {
+	// the app opens the block store and the state DB of --home while it starts
+	viper.Set("home", t.TempDir())
+	t.Cleanup(func() { viper.Set("home", "") })
+	dapp := app.NewBNBBeaconChain(log.NewNopLogger(), dbm.NewMemDB(), io.Discard)
+	ctx := dapp.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	for i := 0; i < n; i++ {
+		addr := make(sdk.AccAddress, 20)
+		addr[0], addr[19] = byte(i*37+1), byte(i)
+		acc := &nodetypes.AppAccount{BaseAccount: auth.BaseAccount{Address: addr, AccountNumber: int64(i)}}
+		if i%5 != 0 {
+			acc.SetCoins(sdk.Coins{sdk.NewCoin("BNB", int64(i*1000)), sdk.NewCoin("XYZ-000", int64(i))})
+			acc.SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", int64(i))})
+		}
+		// the keeper writes to the account cache, the export reads the store
+		ctx.KVStore(common.AccountStoreKey).Set(auth.AddressStoreKey(addr), dapp.Codec.MustMarshalBinaryBare(acc))
+	}
+	return dapp
+}
+
+// rewindCheckpoint rewinds the checkpoint of a finished export to the one
+// saved after the first n accounts. The spilled data of the other accounts is
+// kept, as it is when the export is interrupted after the checkpoint.
+func rewindCheckpoint(t *testing.T, dir string, n int) {
+	data, err := os.ReadFile(path.Join(dir, checkpointFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state checkpointState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path.Join(dir, spillAccountsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	state.LastKey, state.Accounts, state.Leaves, state.AccountsSize, state.LeavesSize = nil, 0, 0, 0, 0
+	reader := bufio.NewReader(file)
+	for i := 0; i < n; i++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		var account types.ExportedAccount
+		if err := json.Unmarshal(line, &account); err != nil {
+			t.Fatal(err)
+		}
+		state.Accounts++
+		state.AccountsSize += int64(len(line))
+		state.LastKey = append(append([]byte{}, accountKeyPrefix...), account.Address...)
+		for _, coin := range account.Coins {
+			if coin.Amount > 0 {
+				state.Leaves++
+			}
+		}
+	}
+
+	leaves, err := os.ReadFile(path.Join(dir, spillLeavesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(leaves, []byte("\n"))
+	for _, line := range lines[:state.Leaves] {
+		state.LeavesSize += int64(len(line))
+	}
+
+	data, err = json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(dir, checkpointFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readExportedState(t *testing.T, dir string) (*types.ExportedAccountState, []byte) {
+	state, err := loadExportedState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofs, err := os.ReadFile(path.Join(dir, "proofs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state, proofs
+}
+
+func TestExportResumeSameRoot(t *testing.T) {
+	dapp := newTestApp(t, 40)
+	excluded, err := loadExcludedRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	single := t.TempDir()
+	if err := ExportAccountsBalanceWithProof(dapp, single, excluded, 0, false); err != nil {
+		t.Fatal(err)
+	}
+	expected, expectedProofs := readExportedState(t, single)
+	if expected.Summary.Holders == 0 {
+		t.Fatal("no holder exported")
+	}
+
+	for _, n := range []int{0, 1, 17, 40} {
+		resumed := t.TempDir()
+		if err := ExportAccountsBalanceWithProof(dapp, resumed, excluded, 0, false); err != nil {
+			t.Fatal(err)
+		}
+		rewindCheckpoint(t, path.Join(resumed, checkpointDirName), n)
+		if err := ExportAccountsBalanceWithProof(dapp, resumed, excluded, 0, true); err != nil {
+			t.Fatalf("resume after %d accounts: %v", n, err)
+		}
+
+		actual, actualProofs := readExportedState(t, resumed)
+		if actual.StateRoot != expected.StateRoot {
+			t.Errorf("resume after %d accounts: state root %s, expected %s", n, actual.StateRoot, expected.StateRoot)
+		}
+		if !bytes.Equal(actualProofs, expectedProofs) {
+			t.Errorf("resume after %d accounts: proofs.json differs from the single pass export", n)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/bnb-chain/node-dump/claim"
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagMnemonicFile = "mnemonic-file"
+	flagKeyFile      = "key-file"
+	flagAccount      = "account"
+	flagIndex        = "index"
+	flagAmount       = "amount"
+	flagRoot         = "root"
+	flagRecipient    = "recipient"
+	flagClaim        = "claim"
+)
+
+// SignClaimCmd signs the claim of a balance with the Beacon Chain key.
+func SignClaimCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-claim [path/claim.json]",
+		Short: "Sign the claim of a balance with the secp256k1 Beacon Chain key",
+		Long: `Sign the claim of a balance with the secp256k1 Beacon Chain key, offline.
+
+The claim binds the Beacon Chain address, the denom, the amount, the state root
+and the BSC recipient. The amount and the root are taken from the export in
+--proofs, or given with --amount and --root. The key is derived from the
+mnemonic in --mnemonic-file at m/44'/714'/account'/0/index, or loaded from
+--key-file, a hex or amino JSON encoded private key. The signed claim is
+written to stdout when no path is given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("at most one <path/claim.json> should be set")
+			}
+			privKey, err := loadClaimKey()
+			if err != nil {
+				return err
+			}
+			if !common.IsHexAddress(viper.GetString(flagRecipient)) {
+				return fmt.Errorf("--%s should be a hex address", flagRecipient)
+			}
+			denom := viper.GetString(flagDenom)
+			if denom == "" {
+				return fmt.Errorf("--%s should be set", flagDenom)
+			}
+
+			message := &claim.Message{
+				Address:   claim.Address(secp256k1.PrivKeySecp256k1(privKey).PubKey().(secp256k1.PubKeySecp256k1)),
+				Denom:     denom,
+				Recipient: common.HexToAddress(viper.GetString(flagRecipient)),
+			}
+			if proofPath := viper.GetString(flagProofs); proofPath != "" {
+				state, exported, err := findExportedProof(proofPath, message.Address, denom)
+				if err != nil {
+					return err
+				}
+				message.Amount = exported.Coin.Amount
+				message.Root = common.HexToHash(state.StateRoot)
+			} else {
+				if !cmd.Flags().Changed(flagAmount) || viper.GetString(flagRoot) == "" {
+					return fmt.Errorf("--%s or --%s and --%s should be set", flagProofs, flagAmount, flagRoot)
+				}
+				root, err := hexutil.Decode(viper.GetString(flagRoot))
+				if err != nil || len(root) != common.HashLength {
+					return fmt.Errorf("--%s should be a 32 bytes hex", flagRoot)
+				}
+				message.Amount = viper.GetInt64(flagAmount)
+				message.Root = common.BytesToHash(root)
+			}
+
+			signed, err := claim.Sign(message, privKey)
+			if err != nil {
+				return err
+			}
+			trace("claim signed", "address", message.Address.String(), "denom", denom, "amount", message.Amount)
+
+			if len(args) == 0 || args[0] == "" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "\t")
+				return encoder.Encode(signed)
+			}
+			file, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			err = writeJSONFile(file, signed)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Signed claim written:", args[0])
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagMnemonicFile, "", "file containing the mnemonic of the Beacon Chain key")
+	cmd.Flags().Uint32(flagAccount, 0, "account of the HD path")
+	cmd.Flags().Uint32(flagIndex, 0, "address index of the HD path")
+	cmd.Flags().String(flagKeyFile, "", "hex or amino JSON encoded secp256k1 private key file")
+	cmd.Flags().String(flagDenom, "", "denom of the claimed balance")
+	cmd.Flags().String(flagProofs, "", "directory of the export to take the amount and the root from")
+	cmd.Flags().Int64(flagAmount, 0, "claimed amount when --proofs is not set")
+	cmd.Flags().String(flagRoot, "", "hex state root when --proofs is not set")
+	cmd.Flags().String(flagRecipient, "", "hex BSC address receiving the balance")
+	return cmd
+}
+
+// VerifyClaimCmd verifies a signed claim.
+func VerifyClaimCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-claim <path/claim.json>",
+		Short: "Verify the signature and the ownership of a signed claim",
+		Long: `Verify the signature and the ownership of a signed claim, offline.
+
+The signature must be made by the public key of the claim, and the public key
+must derive the claimed Beacon Chain address. With --proofs, the amount and the
+root must also match the export and the proof of the balance must be valid.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<path/claim.json> should be set")
+			}
+			signed, err := loadSignedClaim(args[0])
+			if err != nil {
+				return err
+			}
+			if err := claim.Verify(signed); err != nil {
+				return err
+			}
+			if proofPath := viper.GetString(flagProofs); proofPath != "" {
+				state, exported, err := findVerifiedProof(proofPath, signed.Address, signed.Denom)
+				if err != nil {
+					return err
+				}
+				if err := checkClaimAgainstProof(signed, state, exported); err != nil {
+					return err
+				}
+			}
+			fmt.Println("Claim verification passed",
+				"address:", signed.Address.String(),
+				"denom:", signed.Denom,
+				"amount:", signed.Amount,
+				"recipient:", signed.Recipient.Hex())
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagProofs, "", "directory of the export to check the claim against")
+	return cmd
+}
+
+// loadClaimKey loads the secp256k1 private key given by --mnemonic-file or
+// --key-file.
+func loadClaimKey() ([]byte, error) {
+	mnemonicFile := viper.GetString(flagMnemonicFile)
+	keyFile := viper.GetString(flagKeyFile)
+	switch {
+	case mnemonicFile != "" && keyFile != "":
+		return nil, fmt.Errorf("only one of --%s and --%s should be set", flagMnemonicFile, flagKeyFile)
+	case mnemonicFile != "":
+		data, err := os.ReadFile(mnemonicFile)
+		if err != nil {
+			return nil, err
+		}
+		mnemonic := strings.Join(strings.Fields(string(data)), " ")
+		return claim.PrivKeyFromMnemonic(mnemonic, "", viper.GetUint32(flagAccount), viper.GetUint32(flagIndex))
+	case keyFile != "":
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		if privKey, err := hexutil.Decode("0x" + strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")); err == nil {
+			if len(privKey) != 32 {
+				return nil, fmt.Errorf("key file %s should contain a 32 bytes private key", keyFile)
+			}
+			return privKey, nil
+		}
+		signingKey, err := loadSigningKey(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		privKey, ok := signingKey.(secp256k1.PrivKeySecp256k1)
+		if !ok {
+			return nil, fmt.Errorf("key file %s should contain a secp256k1 private key", keyFile)
+		}
+		return privKey, nil
+	default:
+		return nil, fmt.Errorf("--%s or --%s should be set", flagMnemonicFile, flagKeyFile)
+	}
+}
+
+func loadSignedClaim(name string) (*claim.SignedMessage, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var signed claim.SignedMessage
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return nil, fmt.Errorf("decode claim %s: %w", name, err)
+	}
+	return &signed, nil
+}
+
+// checkClaimAgainstProof checks that the claim is the balance proven by the
+// exported proof of the state.
+func checkClaimAgainstProof(signed *claim.SignedMessage, state *types.ExportedAccountState, exported *types.ExportedProof) error {
+	if common.HexToHash(state.StateRoot) != signed.Root {
+		return fmt.Errorf("root mismatch: claim %s, export %s", signed.Root.Hex(), state.StateRoot)
+	}
+	if exported.Coin.Amount != signed.Amount {
+		return fmt.Errorf("amount mismatch: claim %d, export %d", signed.Amount, exported.Coin.Amount)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node-dump/claim"
+	"github.com/bnb-chain/node-dump/proof"
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagAddress              = "address"
+	flagDenom                = "denom"
+	flagProofs               = "proofs"
+	flagContract             = "contract"
+	flagChainID              = "chain-id"
+	flagNonce                = "nonce"
+	flagGas                  = "gas"
+	flagMaxFeePerGas         = "max-fee-per-gas"
+	flagMaxPriorityFeePerGas = "max-priority-fee-per-gas"
+	flagOwnerPubKey          = "owner-pub-key"
+	flagOwnerSignature       = "owner-signature"
+	flagKeystore             = "keystore"
+	flagPasswordFile         = "password-file"
+)
+
+// claimTx is the recovery transaction of a claim, the transactions are the
+// binary encoding of the typed transaction envelope.
+type claimTx struct {
+	From                 string        `json:"from,omitempty"`
+	To                   string        `json:"to"`
+	ChainID              string        `json:"chain_id"`
+	Nonce                uint64        `json:"nonce"`
+	Gas                  uint64        `json:"gas"`
+	MaxFeePerGas         string        `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string        `json:"max_priority_fee_per_gas"`
+	Data                 hexutil.Bytes `json:"data"`
+	StateRoot            string        `json:"state_root"`
+	UnsignedTx           hexutil.Bytes `json:"unsigned_tx"`
+	SignedTx             hexutil.Bytes `json:"signed_tx,omitempty"`
+	Hash                 string        `json:"hash,omitempty"`
+}
+
+// findExportedProof returns the exported state and the proof of the balance of
+// addr in denom from the export in proofPath.
+func findExportedProof(proofPath string, addr sdk.AccAddress, denom string) (*types.ExportedAccountState, *types.ExportedProof, error) {
+	state, proofs, err := findAccountProofs(proofPath, addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, exported := range proofs {
+		if exported.Coin.Denom == denom {
+			return state, exported, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no proof of %s for %s", denom, addr.String())
+}
+
+// findVerifiedProof returns the exported state and the proof of the balance of
+// addr in denom from the export in proofPath, once the proof is verified
+// against the state root.
+func findVerifiedProof(proofPath string, addr sdk.AccAddress, denom string) (*types.ExportedAccountState, *types.ExportedProof, error) {
+	state, exported, err := findExportedProof(proofPath, addr, denom)
+	if err != nil {
+		return nil, nil, err
+	}
+	valid, err := proof.VerifyExportedProof(state.StateRoot, exported)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !valid {
+		return nil, nil, fmt.Errorf("proof of %s for %s does not match the state root %s", denom, addr.String(), state.StateRoot)
+	}
+	return state, exported, nil
+}
+
+// ClaimTxCmd builds the recovery transaction of an exported proof.
+func ClaimTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim-tx",
+		Short: "Build the BSC recovery transaction of an exported proof",
+		Long: `Build the BSC recovery transaction of an exported proof.
+
+The recovery call is ABI encoded with the token symbol, the amount, the owner
+public key and signature and the merkle proof, and wrapped in an unsigned
+EIP-1559 transaction. The owner public key and signature are taken from the
+claim signed by sign-claim with --claim. With --keystore the transaction is
+also signed, the signed transaction can be sent with eth_sendRawTransaction.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var signed *claim.SignedMessage
+			if claimFile := viper.GetString(flagClaim); claimFile != "" {
+				var err error
+				signed, err = loadSignedClaim(claimFile)
+				if err != nil {
+					return err
+				}
+				if err := claim.Verify(signed); err != nil {
+					return err
+				}
+			}
+
+			address, denom := viper.GetString(flagAddress), viper.GetString(flagDenom)
+			if signed != nil {
+				if address == "" {
+					address = signed.Address.String()
+				}
+				if denom == "" {
+					denom = signed.Denom
+				}
+			}
+			addr, err := parseAddress(address)
+			if err != nil {
+				return err
+			}
+			if denom == "" {
+				return fmt.Errorf("--%s should be set", flagDenom)
+			}
+			if !common.IsHexAddress(viper.GetString(flagContract)) {
+				return fmt.Errorf("--%s should be a hex address", flagContract)
+			}
+			if !cmd.Flags().Changed(flagNonce) {
+				return fmt.Errorf("--%s should be set", flagNonce)
+			}
+			state, exported, err := findVerifiedProof(viper.GetString(flagProofs), addr, denom)
+			if err != nil {
+				return err
+			}
+			var ownerPubKey, ownerSignature []byte
+			if signed != nil {
+				if !signed.Address.Equals(addr) || signed.Denom != denom {
+					return fmt.Errorf("claim of %s %s does not match %s %s", signed.Address.String(), signed.Denom, addr.String(), denom)
+				}
+				if err := checkClaimAgainstProof(signed, state, exported); err != nil {
+					return err
+				}
+				ownerPubKey, ownerSignature = signed.PubKey, signed.Signature
+			} else {
+				ownerPubKey, err = hexutil.Decode(viper.GetString(flagOwnerPubKey))
+				if err != nil {
+					return fmt.Errorf("--%s: %w", flagOwnerPubKey, err)
+				}
+				ownerSignature, err = hexutil.Decode(viper.GetString(flagOwnerSignature))
+				if err != nil {
+					return fmt.Errorf("--%s: %w", flagOwnerSignature, err)
+				}
+			}
+			maxFeePerGas, ok := new(big.Int).SetString(viper.GetString(flagMaxFeePerGas), 10)
+			if !ok {
+				return fmt.Errorf("--%s should be an amount of wei", flagMaxFeePerGas)
+			}
+			maxPriorityFeePerGas, ok := new(big.Int).SetString(viper.GetString(flagMaxPriorityFeePerGas), 10)
+			if !ok {
+				return fmt.Errorf("--%s should be an amount of wei", flagMaxPriorityFeePerGas)
+			}
+
+			data, err := claim.PackRecover(exported, ownerPubKey, ownerSignature)
+			if err != nil {
+				return err
+			}
+			params := &claim.TxParams{
+				ChainID:   big.NewInt(viper.GetInt64(flagChainID)),
+				Contract:  common.HexToAddress(viper.GetString(flagContract)),
+				Nonce:     viper.GetUint64(flagNonce),
+				Gas:       viper.GetUint64(flagGas),
+				GasTipCap: maxPriorityFeePerGas,
+				GasFeeCap: maxFeePerGas,
+			}
+			tx := claim.NewRecoverTx(params, data)
+			unsigned, err := tx.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			out := claimTx{
+				To:                   params.Contract.Hex(),
+				ChainID:              params.ChainID.String(),
+				Nonce:                params.Nonce,
+				Gas:                  params.Gas,
+				MaxFeePerGas:         maxFeePerGas.String(),
+				MaxPriorityFeePerGas: maxPriorityFeePerGas.String(),
+				Data:                 data,
+				StateRoot:            state.StateRoot,
+				UnsignedTx:           unsigned,
+			}
+
+			if keystoreFile := viper.GetString(flagKeystore); keystoreFile != "" {
+				key, err := loadKeystore(keystoreFile, viper.GetString(flagPasswordFile))
+				if err != nil {
+					return err
+				}
+				signedTx, err := ethtypes.SignTx(tx, ethtypes.LatestSignerForChainID(params.ChainID), key.PrivateKey)
+				if err != nil {
+					return err
+				}
+				out.SignedTx, err = signedTx.MarshalBinary()
+				if err != nil {
+					return err
+				}
+				out.From = key.Address.Hex()
+				out.Hash = signedTx.Hash().Hex()
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "\t")
+			return encoder.Encode(out)
+		},
+	}
+	cmd.Flags().String(flagAddress, "", "bech32 or hex Beacon Chain address of the claimed balance")
+	cmd.Flags().String(flagDenom, "", "denom of the claimed balance")
+	cmd.Flags().String(flagProofs, "", "directory of the export containing base.json and proofs.json")
+	cmd.Flags().String(flagContract, "", "hex address of the recovery contract")
+	cmd.Flags().Int64(flagChainID, 56, "BSC chain id, 56 for mainnet and 97 for testnet")
+	cmd.Flags().Uint64(flagNonce, 0, "nonce of the sending account")
+	cmd.Flags().Uint64(flagGas, 500000, "gas limit")
+	cmd.Flags().String(flagMaxFeePerGas, "5000000000", "max fee per gas in wei")
+	cmd.Flags().String(flagMaxPriorityFeePerGas, "1000000000", "max priority fee per gas in wei")
+	cmd.Flags().String(flagClaim, "", "claim signed by sign-claim, instead of --owner-pub-key and --owner-signature")
+	cmd.Flags().String(flagOwnerPubKey, "", "hex compressed secp256k1 public key of the Beacon Chain address")
+	cmd.Flags().String(flagOwnerSignature, "", "hex signature of the claim by the Beacon Chain address")
+	cmd.Flags().String(flagKeystore, "", "keystore file to sign the transaction with")
+	cmd.Flags().String(flagPasswordFile, "", "file containing the password of the keystore")
+	return cmd
+}
+
+// loadKeystore decrypts a keystore file, the password file is optional.
+func loadKeystore(keystoreFile string, passwordFile string) (*keystore.Key, error) {
+	data, err := os.ReadFile(keystoreFile)
+	if err != nil {
+		return nil, err
+	}
+	password := ""
+	if passwordFile != "" {
+		content, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		password = strings.TrimRight(string(content), "\r\n")
+	}
+	key, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt keystore %s: %w", keystoreFile, err)
+	}
+	return key, nil
+}
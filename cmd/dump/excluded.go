@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	tmCrypto "github.com/tendermint/tendermint/crypto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	nodetypes "github.com/bnb-chain/node/common/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagExcludeFile = "exclude-file"
+
+	excludedFile = "excluded.json"
+)
+
+// defaultExcludedAccounts are the escrow accounts of the modules and the
+// addresses nobody holds the key of.
+func defaultExcludedAccounts() []*types.ExcludedAccount {
+	return []*types.ExcludedAccount{
+		{
+			// bnb prefix address: bnb1vu5max8wqn997ayhrrys0drpll2rlz4dh39s3h
+			// tbnb prefix address: tbnb1vu5max8wqn997ayhrrys0drpll2rlz4deyv53x
+			Address: sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainDepositedCoins"))),
+			Label:   "depositedCoinsAccAddr",
+			Reason:  "escrow of the governance proposal deposits",
+		},
+		{
+			// bnb prefix address: bnb1j725qk29cv4kwpers4addy9x93ukhw7czfkjaj
+			// tbnb prefix address: tbnb1j725qk29cv4kwpers4addy9x93ukhw7cvulkar
+			Address: sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainStakeDelegation"))),
+			Label:   "delegationAccAddr",
+			Reason:  "escrow of the delegated stakes",
+		},
+		{
+			// bnb prefix address: bnb1v8vkkymvhe2sf7gd2092ujc6hweta38xadu2pj
+			// tbnb prefix address: tbnb1v8vkkymvhe2sf7gd2092ujc6hweta38xnc4wpr
+			Address: sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainPegAccount"))),
+			Label:   "pegAccount",
+			Reason:  "escrow of the tokens transferred to BNB Smart Chain",
+		},
+		{
+			// bnb prefix address: bnb1wxeplyw7x8aahy93w96yhwm7xcq3ke4f8ge93u
+			// tbnb prefix address: tbnb1wxeplyw7x8aahy93w96yhwm7xcq3ke4ffasp3d
+			Address: sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainAtomicSwapCoins"))),
+			Label:   "atomicSwapCoinsAccAddr",
+			Reason:  "escrow of the open atomic swaps",
+		},
+		{
+			// bnb prefix address: bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq
+			// tbnb prefix address: tbnb1hn8ym9xht925jkncjpf7lhjnax6z8nv2mu9wy3
+			Address: sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainTimeLockCoins"))),
+			Label:   "timeLockCoinsAccAddr",
+			Reason:  "escrow of the time locked coins",
+		},
+		{
+			// nil address
+			Address: sdk.AccAddress(tmCrypto.AddressHash([]byte(nil))),
+			Label:   "emptyAccAddr",
+			Reason:  "hash of empty bytes, nobody holds its key",
+		},
+		{
+			// 0x0000... address
+			Address: sdk.AccAddress(make([]byte, 20)),
+			Label:   "zeroAccAddr",
+			Reason:  "zero address, nobody holds its key",
+		},
+	}
+}
+
+// excludedRegistry is the list of the accounts left out of an export.
+type excludedRegistry struct {
+	accounts []*types.ExcludedAccount
+	index    map[string]*types.ExcludedAccount
+}
+
+// newExcludedRegistry returns the registry of the given accounts, an address
+// must not be listed twice.
+func newExcludedRegistry(accounts []*types.ExcludedAccount) (*excludedRegistry, error) {
+	registry := &excludedRegistry{
+		accounts: accounts,
+		index:    make(map[string]*types.ExcludedAccount, len(accounts)),
+	}
+	for _, account := range accounts {
+		if existing, exist := registry.index[account.Address.String()]; exist {
+			return nil, fmt.Errorf("excluded address %s is listed twice, as %q and %q",
+				account.Address.String(), existing.Label, account.Label)
+		}
+		registry.index[account.Address.String()] = account
+	}
+	return registry, nil
+}
+
+// loadExcludedRegistry returns the registry of the default excluded accounts
+// and the accounts of excludeFile when it is not empty.
+func loadExcludedRegistry(excludeFile string) (*excludedRegistry, error) {
+	accounts := defaultExcludedAccounts()
+	if excludeFile != "" {
+		extra, err := loadExcludeFile(excludeFile)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, extra...)
+	}
+	return newExcludedRegistry(accounts)
+}
+
+// Lookup returns the excluded account of addr.
+func (r *excludedRegistry) Lookup(addr sdk.AccAddress) (*types.ExcludedAccount, bool) {
+	account, exist := r.index[addr.String()]
+	return account, exist
+}
+
+// Accounts returns the excluded accounts in the order they were listed.
+func (r *excludedRegistry) Accounts() []*types.ExcludedAccount {
+	return r.accounts
+}
+
+// Trace logs the excluded accounts.
+func (r *excludedRegistry) Trace() {
+	for _, account := range r.accounts {
+		trace("excluded account", account.Label+":", account.Address.String(), "reason:", account.Reason)
+	}
+}
+
+// excludeFileEntry is an entry of the exclude file, the address is either
+// bech32 or hex encoded.
+type excludeFileEntry struct {
+	Address string `json:"address" yaml:"address"`
+	Label   string `json:"label" yaml:"label"`
+	Reason  string `json:"reason" yaml:"reason"`
+}
+
+// loadExcludeFile loads a YAML or JSON list of excluded accounts, the format
+// is chosen by the file extension.
+func loadExcludeFile(name string) ([]*types.ExcludedAccount, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var entries []excludeFileEntry
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		err = json.Unmarshal(data, &entries)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &entries)
+	default:
+		return nil, fmt.Errorf("exclude file %s should be .yaml, .yml or .json", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode exclude file %s: %w", name, err)
+	}
+
+	accounts := make([]*types.ExcludedAccount, 0, len(entries))
+	for i, entry := range entries {
+		if entry.Label == "" || entry.Reason == "" {
+			return nil, fmt.Errorf("entry %d of exclude file %s should have a label and a reason", i, name)
+		}
+		addr, err := parseAddress(entry.Address)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d of exclude file %s: %w", i, name, err)
+		}
+		accounts = append(accounts, &types.ExcludedAccount{
+			Address: addr,
+			Label:   entry.Label,
+			Reason:  entry.Reason,
+		})
+	}
+	return accounts, nil
+}
+
+// parseAddress parses a bech32 or a hex encoded account address.
+func parseAddress(address string) (sdk.AccAddress, error) {
+	if addr, err := sdk.AccAddressFromBech32(address); err == nil {
+		return addr, nil
+	}
+	addr, err := sdk.AccAddressFromHex(strings.TrimPrefix(address, "0x"))
+	if err != nil || len(addr) != 20 {
+		return nil, fmt.Errorf("invalid address %q, expected bech32 or 20 bytes hex", address)
+	}
+	return addr, nil
+}
+
+// excludedBalances returns the balances of the excluded accounts, getAccount
+// returns nil for an account that does not exist.
+func excludedBalances(registry *excludedRegistry, getAccount func(addr sdk.AccAddress) sdk.Account) []*types.ExcludedBalance {
+	balances := make([]*types.ExcludedBalance, 0, len(registry.Accounts()))
+	for _, account := range registry.Accounts() {
+		balance := &types.ExcludedBalance{
+			ExcludedAccount: *account,
+			Coins:           sdk.Coins{},
+		}
+		if acc := getAccount(account.Address); acc != nil {
+			_, _, _, balance.Coins = accountBalances(acc.(nodetypes.NamedAccount))
+		}
+		balances = append(balances, balance)
+	}
+	return balances
+}
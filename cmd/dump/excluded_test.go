@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+func testAddress(b byte) sdk.AccAddress {
+	addr := make(sdk.AccAddress, 20)
+	addr[0] = b
+	return addr
+}
+
+func TestLoadExcludeFile(t *testing.T) {
+	first, second := testAddress(1), testAddress(2)
+	json := `[
+	{"address": "` + first.String() + `", "label": "first", "reason": "bech32"},
+	{"address": "0x` + hex.EncodeToString(second) + `", "label": "second", "reason": "hex"}
+]`
+	yaml := `
+- address: ` + first.String() + `
+  label: first
+  reason: bech32
+- address: "0x` + hex.EncodeToString(second) + `"
+  label: second
+  reason: hex
+`
+	for _, test := range []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{name: "excluded.json", content: json},
+		{name: "excluded.JSON", content: json},
+		{name: "excluded.yaml", content: yaml},
+		{name: "excluded.yml", content: yaml},
+		{name: "excluded.txt", content: json, err: "should be .yaml, .yml or .json"},
+		{name: "broken.json", content: yaml, err: "decode exclude file"},
+		{name: "unlabeled.yaml", content: "- address: " + first.String() + "\n  reason: bech32\n", err: "should have a label and a reason"},
+		{name: "invalid.yaml", content: "- address: bnb1invalid\n  label: invalid\n  reason: invalid\n", err: "invalid address"},
+	} {
+		path := filepath.Join(t.TempDir(), test.name)
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		accounts, err := loadExcludeFile(path)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		expected := []*types.ExcludedAccount{
+			{Address: first, Label: "first", Reason: "bech32"},
+			{Address: second, Label: "second", Reason: "hex"},
+		}
+		if len(accounts) != len(expected) {
+			t.Errorf("%s: %d accounts, want %d", test.name, len(accounts), len(expected))
+			continue
+		}
+		for i, account := range accounts {
+			if !account.Address.Equals(expected[i].Address) || account.Label != expected[i].Label || account.Reason != expected[i].Reason {
+				t.Errorf("%s: account %d is %+v, want %+v", test.name, i, account, expected[i])
+			}
+		}
+	}
+}
+
+func TestNewExcludedRegistry(t *testing.T) {
+	accounts := append(defaultExcludedAccounts(), &types.ExcludedAccount{Address: testAddress(1), Label: "extra", Reason: "test"})
+	registry, err := newExcludedRegistry(accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account, exist := registry.Lookup(testAddress(1)); !exist || account.Label != "extra" {
+		t.Errorf("lookup of the extra account returned %+v, %v", account, exist)
+	}
+	if _, exist := registry.Lookup(testAddress(2)); exist {
+		t.Error("lookup of an account not listed succeeded")
+	}
+
+	// the zero address is one of the defaults
+	duplicate := append(defaultExcludedAccounts(), &types.ExcludedAccount{Address: make(sdk.AccAddress, 20), Label: "zero", Reason: "test"})
+	if _, err := newExcludedRegistry(duplicate); err == nil || !strings.Contains(err.Error(), "listed twice") {
+		t.Errorf("registry of a duplicated address: %v", err)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	addr := testAddress(1)
+	for _, test := range []struct {
+		address string
+		valid   bool
+	}{
+		{address: addr.String(), valid: true},
+		{address: hex.EncodeToString(addr), valid: true},
+		{address: "0x" + hex.EncodeToString(addr), valid: true},
+		{address: strings.ToUpper(hex.EncodeToString(addr)), valid: true},
+		{address: hex.EncodeToString(addr[:19])},
+		{address: "0x" + hex.EncodeToString(addr) + "00"},
+		{address: addr.String()[:len(addr.String())-1]},
+		{address: "not an address"},
+		{address: ""},
+	} {
+		parsed, err := parseAddress(test.address)
+		switch {
+		case test.valid && err != nil:
+			t.Errorf("parse %q: %v", test.address, err)
+		case test.valid && !parsed.Equals(addr):
+			t.Errorf("parse %q: %s, want %s", test.address, parsed, addr)
+		case !test.valid && err == nil:
+			t.Errorf("parse %q succeeded", test.address)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	tmCrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+
+	"github.com/bnb-chain/node-dump/types"
+	"github.com/bnb-chain/node-dump/version"
+)
+
+const (
+	flagSignKey             = "sign-key"
+	flagSigner              = "signer"
+	flagAllowUnpinnedSigner = "allow-unpinned-signer"
+	flagAllowUnsigned       = "allow-unsigned"
+
+	manifestFile = "manifest.json"
+)
+
+// exportedFiles are the files bound by the export manifest.
+var exportedFiles = []string{"base.json", "accounts.json", "proofs.json", excludedFile}
+
+// WriteExportManifest writes the manifest of the exported files, signed with
+// the given key when it is not nil.
+func WriteExportManifest(outputPath string, privKey tmCrypto.PrivKey) error {
+	stateFile, err := os.Open(path.Join(outputPath, "base.json"))
+	if err != nil {
+		return err
+	}
+	defer stateFile.Close()
+	var state types.ExportedAccountState
+	err = json.NewDecoder(stateFile).Decode(&state)
+	if err != nil {
+		return err
+	}
+
+	manifest := types.ExportManifest{
+		Version: version.Version,
+		State:   state,
+		Files:   make([]*types.ExportedFile, 0, len(exportedFiles)),
+	}
+	for _, name := range exportedFiles {
+		file, err := hashExportedFile(outputPath, name)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	signed := types.SignedExportManifest{
+		Manifest: data,
+	}
+	if privKey != nil {
+		signed.KeyType, err = keyType(privKey)
+		if err != nil {
+			return err
+		}
+		signed.Signature, err = privKey.Sign(data)
+		if err != nil {
+			return err
+		}
+		signed.PubKey = privKey.PubKey().Bytes()
+		raw, err := rawPubKey(privKey.PubKey())
+		if err != nil {
+			return err
+		}
+		trace("manifest signed", "key type", signed.KeyType, "pub key", hex.EncodeToString(raw))
+	}
+
+	file, err := os.OpenFile(path.Join(outputPath, manifestFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeJSONFile(file, signed)
+}
+
+// VerifyExportManifest checks the signature of the manifest and the hashes of
+// the exported files, and returns the manifest with the public key that signed
+// it, nil when unsigned. signer pins the expected public key, the hex encoded
+// raw key or its amino encoding. The manifest carries the key that signed it,
+// so it is only trusted without signer when allowUnpinned is set.
+func VerifyExportManifest(proofPath string, signer string, allowUnpinned bool, allowUnsigned bool) (*types.ExportManifest, tmCrypto.PubKey, error) {
+	data, err := os.ReadFile(path.Join(proofPath, manifestFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	var signed types.SignedExportManifest
+	err = json.Unmarshal(data, &signed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the signature covers the compact encoding
+	var manifestData bytes.Buffer
+	err = json.Compact(&manifestData, signed.Manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pubKey tmCrypto.PubKey
+	if len(signed.Signature) == 0 {
+		if !allowUnsigned {
+			return nil, nil, fmt.Errorf("manifest is not signed")
+		}
+		trace("WARNING: manifest is not signed")
+	} else {
+		pubKey, err = cryptoAmino.PubKeyFromBytes(signed.PubKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode public key: %w", err)
+		}
+		raw, err := rawPubKey(pubKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case signer != "":
+			pinned, err := hex.DecodeString(strings.TrimPrefix(signer, "0x"))
+			if err != nil {
+				return nil, nil, fmt.Errorf("decode signer: %w", err)
+			}
+			if !bytes.Equal(pinned, raw) && !bytes.Equal(pinned, signed.PubKey) {
+				return nil, nil, fmt.Errorf("signer mismatch: expected %s, actual %s", signer, hex.EncodeToString(raw))
+			}
+		case !allowUnpinned:
+			return nil, nil, fmt.Errorf("manifest signed by %s is not pinned, set --%s to the published key of the operator or --%s",
+				hex.EncodeToString(raw), flagSigner, flagAllowUnpinnedSigner)
+		default:
+			trace("WARNING: manifest signer is not pinned")
+		}
+		if !pubKey.VerifyBytes(manifestData.Bytes(), signed.Signature) {
+			return nil, nil, fmt.Errorf("invalid manifest signature")
+		}
+		trace("manifest signature verified", "key type", signed.KeyType, "pub key", hex.EncodeToString(raw))
+	}
+
+	var manifest types.ExportManifest
+	err = json.Unmarshal(manifestData.Bytes(), &manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, expected := range manifest.Files {
+		actual, err := hashExportedFile(proofPath, expected.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if actual.Size != expected.Size || actual.SHA256 != expected.SHA256 {
+			return nil, nil, fmt.Errorf("file %s mismatch: expected size %d sha256 %s, actual size %d sha256 %s",
+				expected.Name, expected.Size, expected.SHA256, actual.Size, actual.SHA256)
+		}
+		trace("file verified", expected.Name, "size", actual.Size, "sha256", actual.SHA256)
+	}
+
+	return &manifest, pubKey, nil
+}
+
+func hashExportedFile(dir string, name string) (*types.ExportedFile, error) {
+	file, err := os.Open(path.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ExportedFile{
+		Name:   name,
+		Size:   size,
+		SHA256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// loadSigningKey loads an amino JSON encoded ed25519 or secp256k1 private key,
+// either bare or in the `priv_key` field of a priv_validator_key.json file.
+func loadSigningKey(keyFile string) (tmCrypto.PrivKey, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var validatorKey struct {
+		PrivKey json.RawMessage `json:"priv_key"`
+	}
+	if err := json.Unmarshal(data, &validatorKey); err == nil && len(validatorKey.PrivKey) > 0 {
+		data = validatorKey.PrivKey
+	}
+
+	var privKey tmCrypto.PrivKey
+	err = app.Codec.UnmarshalJSON(data, &privKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode signing key %s: %w", keyFile, err)
+	}
+	if _, err := keyType(privKey); err != nil {
+		return nil, err
+	}
+	return privKey, nil
+}
+
+// rawPubKey returns the raw bytes of an ed25519 or secp256k1 public key,
+// without the amino prefix of PubKey.Bytes().
+func rawPubKey(pubKey tmCrypto.PubKey) ([]byte, error) {
+	switch key := pubKey.(type) {
+	case ed25519.PubKeyEd25519:
+		return key[:], nil
+	case secp256k1.PubKeySecp256k1:
+		return key[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T, expected ed25519 or secp256k1", pubKey)
+	}
+}
+
+func keyType(privKey tmCrypto.PrivKey) (string, error) {
+	switch privKey.(type) {
+	case ed25519.PrivKeyEd25519:
+		return "ed25519", nil
+	case secp256k1.PrivKeySecp256k1:
+		return "secp256k1", nil
+	default:
+		return "", fmt.Errorf("unsupported signing key type %T, expected ed25519 or secp256k1", privKey)
+	}
+}
+
+// VerifyManifestCmd verifies the signed manifest of the exported files.
+func VerifyManifestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-manifest <path>",
+		Short: "Verify the signature and file hashes of the export manifest",
+		Long: `Verify the signature and the file hashes of the manifest of an export.
+
+The manifest carries the public key that signed it, anyone editing the export
+can sign it again with their own key. The key is therefore pinned with
+--signer, the hex encoded raw public key of the operator, 32 bytes for ed25519
+and 33 compressed bytes for secp256k1, the amino encoding is also accepted.
+Without --signer, the manifest is only trusted with --allow-unpinned-signer,
+and the key that signed it is always printed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("<proof path> should be set")
+			}
+			if args[0] == "" {
+				return fmt.Errorf("<proof path> should be set")
+			}
+
+			manifest, pubKey, err := VerifyExportManifest(args[0], viper.GetString(flagSigner),
+				viper.GetBool(flagAllowUnpinnedSigner), viper.GetBool(flagAllowUnsigned))
+			if err != nil {
+				return err
+			}
+			if pubKey == nil {
+				fmt.Println("WARNING: the manifest is not signed, only the file hashes are checked")
+			} else {
+				raw, _ := rawPubKey(pubKey)
+				fmt.Println("Manifest signed by",
+					"pub key:", hex.EncodeToString(raw),
+					"address:", sdk.AccAddress(pubKey.Address()).String())
+			}
+			fmt.Println("Manifest verification passed",
+				"version:", manifest.Version,
+				"chain id:", manifest.State.ChainID,
+				"block height:", manifest.State.BlockHeight,
+				"state root:", manifest.State.StateRoot)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagSigner, "", "hex encoded raw public key the manifest must be signed with")
+	cmd.Flags().Bool(flagAllowUnpinnedSigner, false, "trust the public key carried by the manifest when --signer is not set")
+	cmd.Flags().Bool(flagAllowUnsigned, false, "only check the file hashes of an unsigned manifest")
+	return cmd
+}
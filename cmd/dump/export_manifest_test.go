@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/hex"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	tmCrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// writeTestExport writes the files bound by the manifest into a new directory.
+func writeTestExport(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.json":     `{"chain_id":"Binance-Chain-Test","block_height":10,"commit_id":{"version":10,"hash":""},"state_root":"0x01"}`,
+		"accounts.json": `[]`,
+		"proofs.json":   `[]`,
+		excludedFile:    `[]`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(path.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func rawKeyHex(t *testing.T, privKey tmCrypto.PrivKey) string {
+	raw, err := rawPubKey(privKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(raw)
+}
+
+func TestExportManifestRoundTrip(t *testing.T) {
+	for _, privKey := range []tmCrypto.PrivKey{ed25519.GenPrivKey(), secp256k1.GenPrivKey()} {
+		dir := writeTestExport(t)
+		if err := WriteExportManifest(dir, privKey); err != nil {
+			t.Fatal(err)
+		}
+
+		// the raw key and its amino encoding are both accepted
+		for _, signer := range []string{rawKeyHex(t, privKey), hex.EncodeToString(privKey.PubKey().Bytes())} {
+			manifest, pubKey, err := VerifyExportManifest(dir, signer, false, false)
+			if err != nil {
+				t.Fatalf("%T: %v", privKey, err)
+			}
+			if !pubKey.Equals(privKey.PubKey()) {
+				t.Errorf("%T: unexpected signer %v", privKey, pubKey)
+			}
+			if manifest.State.BlockHeight != 10 || len(manifest.Files) != len(exportedFiles) {
+				t.Errorf("%T: unexpected manifest %+v", privKey, manifest)
+			}
+		}
+	}
+}
+
+func TestExportManifestTamperedFile(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	dir := writeTestExport(t)
+	if err := WriteExportManifest(dir, privKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(dir, "proofs.json"), []byte(`[{}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := VerifyExportManifest(dir, rawKeyHex(t, privKey), false, false)
+	if err == nil || !strings.Contains(err.Error(), "file proofs.json mismatch") {
+		t.Fatalf("expected a file mismatch, got %v", err)
+	}
+}
+
+func TestExportManifestWrongSigner(t *testing.T) {
+	privKey, other := secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	dir := writeTestExport(t)
+	if err := WriteExportManifest(dir, privKey); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := VerifyExportManifest(dir, rawKeyHex(t, other), false, false)
+	if err == nil || !strings.Contains(err.Error(), "signer mismatch") {
+		t.Fatalf("expected a signer mismatch, got %v", err)
+	}
+
+	// re-signed by another key, the manifest is only trusted when unpinned
+	if err := WriteExportManifest(dir, other); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = VerifyExportManifest(dir, rawKeyHex(t, privKey), false, false)
+	if err == nil || !strings.Contains(err.Error(), "signer mismatch") {
+		t.Fatalf("expected a signer mismatch, got %v", err)
+	}
+	_, _, err = VerifyExportManifest(dir, "", false, false)
+	if err == nil || !strings.Contains(err.Error(), "is not pinned") {
+		t.Fatalf("expected an unpinned signer to be refused, got %v", err)
+	}
+	_, pubKey, err := VerifyExportManifest(dir, "", true, false)
+	if err != nil || !pubKey.Equals(other.PubKey()) {
+		t.Fatalf("expected the unpinned signer to be returned, got %v: %v", pubKey, err)
+	}
+}
+
+func TestExportManifestUnsigned(t *testing.T) {
+	dir := writeTestExport(t)
+	if err := WriteExportManifest(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := VerifyExportManifest(dir, "", false, false)
+	if err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("expected an unsigned manifest to be refused, got %v", err)
+	}
+	manifest, pubKey, err := VerifyExportManifest(dir, "", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pubKey != nil || manifest.State.ChainID != "Binance-Chain-Test" {
+		t.Errorf("unexpected manifest %+v signed by %v", manifest, pubKey)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node-dump/types"
+	"github.com/bnb-chain/node-dump/util"
+)
+
+// loadExportedState reads the base.json of the export in dir.
+func loadExportedState(dir string) (*types.ExportedAccountState, error) {
+	file, err := os.Open(path.Join(dir, "base.json"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var state types.ExportedAccountState
+	if err := json.NewDecoder(file).Decode(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// findAccountProofs returns the exported state and the proofs of the balances
+// of addr from the export in proofPath.
+func findAccountProofs(proofPath string, addr sdk.AccAddress) (*types.ExportedAccountState, []*types.ExportedProof, error) {
+	state, err := loadExportedState(proofPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream := util.NewJSONStream(func() any {
+		return &types.ExportedProof{}
+	})
+	go stream.Start(path.Join(proofPath, "proofs.json"))
+
+	proofs := make([]*types.ExportedProof, 0)
+	for data := range stream.Watch() {
+		if data.Error != nil {
+			return nil, nil, data.Error
+		}
+		exported := data.Data.(*types.ExportedProof)
+		if exported.Address.Equals(addr) {
+			proofs = append(proofs, exported)
+		}
+	}
+	return state, proofs, nil
+}
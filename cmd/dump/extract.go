@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/bnb-chain/node-dump/snapshot"
+)
+
+const (
+	flagSegments = "segments"
+
+	spoolDirName = ".dump-segments"
+)
+
+// ExtractCmd extracts the segmented snapshot into the home directory.
+func ExtractCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extract",
+		Short: "Stream-extract the snapshot segments into the home directory",
+		Long: `Stream-extract the snapshot segments into the home directory.
+
+The segments are read from --segments when it is set, otherwise they are
+streamed from the published links. The archive is never written to disk as a
+whole, and its SHA256 is checked against the published value. With --manifest,
+every segment is verified as it arrives and corrupted segments are downloaded
+again. An interrupted extraction is resumed by running the same command again,
+from a checkpoint taken after the start of the last segment reached: only that
+segment is read again, the segments before it are not downloaded again. The
+archive cannot write outside the home
+directory or through a symbolic link.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network, err := snapshot.GetNetwork(viper.GetString(flagNetwork))
+			if err != nil {
+				return err
+			}
+			home := viper.GetString(cli.HomeFlag)
+
+			fetcher := snapshot.NewFetcher()
+			fetcher.Retries = viper.GetInt(flagRetries)
+			fetcher.Trace = trace
+
+			manifest, err := loadManifest()
+			if err != nil {
+				return err
+			}
+			fetcher.Manifest = manifest
+
+			source, err := segmentSource(network, fetcher)
+			if err != nil {
+				return err
+			}
+			spoolDir := filepath.Join(home, spoolDirName)
+			if _, remote := source.(*snapshot.RemoteSource); remote && manifest != nil {
+				// verify each segment before it is extracted, download again if corrupted
+				source = &snapshot.SpoolSource{Fetcher: fetcher, Links: network.Segments, Dir: spoolDir}
+			}
+
+			extractor := &snapshot.Extractor{
+				Source:     source,
+				Home:       home,
+				SHA256:     network.SHA256,
+				Retries:    fetcher.Retries,
+				RetryDelay: fetcher.RetryDelay,
+				Manifest:   manifest,
+				Trace:      trace,
+			}
+			trace("extract snapshot...", "network", network.Name, "home", home)
+			err = extractor.Extract(context.Background())
+			if err != nil {
+				return err
+			}
+			err = os.RemoveAll(spoolDir)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Snapshot extracted and verified:", home)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagNetwork, "mainnet", "snapshot network, mainnet or testnet")
+	cmd.Flags().String(flagSegments, "", "directory of the downloaded segments, stream from the published links if empty")
+	cmd.Flags().Int(flagRetries, 5, "number of retries for each segment")
+	cmd.Flags().String(flagManifest, "", "segment manifest to verify each segment against")
+	return cmd
+}
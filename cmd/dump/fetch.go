@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bnb-chain/node-dump/snapshot"
+)
+
+const (
+	flagNetwork      = "network"
+	flagOut          = "out"
+	flagParallel     = "parallel"
+	flagRetries      = "retries"
+	flagKeepSegments = "keep-segments"
+	flagSkipAssemble = "skip-assemble"
+)
+
+// FetchCmd downloads the snapshot segments and reassembles the archive.
+func FetchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fetch",
+		Short: "Download the snapshot segments and reassemble the archive",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network, err := snapshot.GetNetwork(viper.GetString(flagNetwork))
+			if err != nil {
+				return err
+			}
+			out := viper.GetString(flagOut)
+			if out == "" {
+				return fmt.Errorf("--%s should be set", flagOut)
+			}
+
+			fetcher := snapshot.NewFetcher()
+			fetcher.Parallel = viper.GetInt(flagParallel)
+			fetcher.Retries = viper.GetInt(flagRetries)
+			fetcher.Trace = trace
+			fetcher.Manifest, err = loadManifest()
+			if err != nil {
+				return err
+			}
+
+			segmentDir := filepath.Join(out, "segments")
+			trace("fetch segments...", "network", network.Name, "segments", len(network.Segments))
+			segments, err := fetcher.FetchSegments(context.Background(), network.Segments, segmentDir)
+			if err != nil {
+				return err
+			}
+			if viper.GetBool(flagSkipAssemble) {
+				fmt.Println("Segments downloaded:", segmentDir)
+				return nil
+			}
+
+			archive := filepath.Join(out, network.Archive)
+			trace("assemble archive...", archive)
+			err = snapshot.Assemble(segments, archive, network.SHA256)
+			if err != nil {
+				return err
+			}
+
+			if !viper.GetBool(flagKeepSegments) {
+				err = os.RemoveAll(segmentDir)
+				if err != nil {
+					return err
+				}
+			}
+			fmt.Println("Archive downloaded and verified:", archive)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagNetwork, "mainnet", "snapshot network, mainnet or testnet")
+	cmd.Flags().String(flagOut, "", "directory to download the snapshot into")
+	cmd.Flags().Int(flagParallel, 4, "number of segments downloaded in parallel")
+	cmd.Flags().Int(flagRetries, 5, "number of retries for each segment")
+	cmd.Flags().Bool(flagKeepSegments, false, "keep the downloaded segments after reassembling")
+	cmd.Flags().String(flagManifest, "", "segment manifest to verify each segment against")
+	cmd.Flags().Bool(flagSkipAssemble, false, "only download the segments, e.g. for the extract command")
+	return cmd
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// newGovKeeper returns a read only governance keeper over the store of the app,
+// the votes are tallied with the stake keeper.
+func newGovKeeper(dapp *app.BNBBeaconChain, stakeKeeper stake.Keeper) (gov.Keeper, error) {
+	govSpace, ok := dapp.ParamHub.GetSubspace(gov.DefaultParamSpace)
+	if !ok {
+		return gov.Keeper{}, fmt.Errorf("param subspace %s is not registered", gov.DefaultParamSpace)
+	}
+	return gov.NewKeeper(dapp.Codec, common.GovStoreKey, dapp.ParamHub.Keeper, govSpace,
+		dapp.CoinKeeper, stakeKeeper, gov.DefaultCodespace, nil), nil
+}
+
+// exportProposals exports the proposals in the store of ctx, ctx has the key
+// prefix of the side chain when sideChainID is set.
+func exportProposals(app *app.BNBBeaconChain, keeper gov.Keeper, ctx sdk.Context, sideChainID string) []*types.ExportedProposal {
+	proposals := make([]*types.ExportedProposal, 0)
+	lastID := keeper.GetLastProposalID(ctx)
+	for id := int64(0); id <= lastID; id++ {
+		proposal := keeper.GetProposal(ctx, id)
+		if proposal == nil {
+			continue
+		}
+		exported := &types.ExportedProposal{
+			SideChainID:     sideChainID,
+			ID:              proposal.GetProposalID(),
+			Type:            proposal.GetProposalType(),
+			Title:           proposal.GetTitle(),
+			Description:     proposal.GetDescription(),
+			Status:          proposal.GetStatus(),
+			SubmitTime:      proposal.GetSubmitTime(),
+			TotalDeposit:    proposal.GetTotalDeposit(),
+			VotingStartTime: proposal.GetVotingStartTime(),
+			VotingPeriod:    proposal.GetVotingPeriod(),
+			Tally:           proposal.GetTallyResult(),
+			Deposits:        make([]gov.Deposit, 0),
+			Votes:           make([]gov.Vote, 0),
+		}
+
+		iter := keeper.GetDeposits(ctx, id)
+		for ; iter.Valid(); iter.Next() {
+			var deposit gov.Deposit
+			app.Codec.MustUnmarshalBinaryLengthPrefixed(iter.Value(), &deposit)
+			exported.Deposits = append(exported.Deposits, deposit)
+		}
+		iter.Close()
+		iter = keeper.GetVotes(ctx, id)
+		for ; iter.Valid(); iter.Next() {
+			var vote gov.Vote
+			app.Codec.MustUnmarshalBinaryLengthPrefixed(iter.Value(), &vote)
+			exported.Votes = append(exported.Votes, vote)
+		}
+		iter.Close()
+
+		// tallying deletes the votes, the writes are discarded with the cache
+		if proposal.GetStatus() == gov.StatusVotingPeriod {
+			cacheCtx, _ := ctx.CacheContext()
+			_, _, exported.Tally = gov.Tally(cacheCtx, keeper, proposal)
+		}
+		proposals = append(proposals, exported)
+	}
+	return proposals
+}
+
+// ExportGov exports the governance proposals of the Beacon Chain and of the
+// side chains.
+func ExportGov(app *app.BNBBeaconChain) (*types.ExportedGov, error) {
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	stakeKeeper, sideChainKeeper, err := newStakeKeepers(app)
+	if err != nil {
+		return nil, err
+	}
+	govKeeper, err := newGovKeeper(app, stakeKeeper)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := &types.ExportedGov{
+		ChainID:     app.CheckState.Ctx.ChainID(),
+		BlockHeight: app.LastBlockHeight(),
+		Proposals:   exportProposals(app, govKeeper, ctx, ""),
+	}
+	sideChainIDs, prefixes := sideChainKeeper.GetAllSideChainPrefixes(ctx)
+	for i, sideChainID := range sideChainIDs {
+		sideCtx := ctx.WithSideChainKeyPrefix(prefixes[i])
+		exported.Proposals = append(exported.Proposals, exportProposals(app, govKeeper, sideCtx, sideChainID)...)
+	}
+	trace("proposals", len(exported.Proposals))
+	return exported, nil
+}
+
+// ExportGovCmd exports the governance proposals.
+func ExportGovCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-gov <path>",
+		Short: "Export the governance proposals, deposits and votes",
+		Long: `Export the governance proposals of the Beacon Chain and of the side chains at
+the last block height.
+
+Each proposal is exported with its type, content, status, total deposit and
+tally, and the deposits and votes still recorded in the store. The deposits and
+votes of a proposal are removed when its voting ends, the tally is then the
+final tally of the proposal. The proposals are written to <path>/gov.json. The
+application DB is opened read only.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<path> should be set")
+			}
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			exported, err := ExportGov(dapp)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(args[0], 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(path.Join(args[0], "gov.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			err = writeJSONFile(file, exported)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Proposals exported:", len(exported.Proposals), "height:", exported.BlockHeight)
+
+			return nil
+		},
+	}
+	return cmd
+}
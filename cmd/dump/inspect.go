@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	stakekeeper "github.com/cosmos/cosmos-sdk/x/stake/keeper"
+	staketypes "github.com/cosmos/cosmos-sdk/x/stake/types"
+
+	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common"
+	nodetypes "github.com/bnb-chain/node/common/types"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagStore  = "store"
+	flagPrefix = "prefix"
+	flagLimit  = "limit"
+)
+
+// valueDecoder decodes the values of the keys starting with prefix.
+type valueDecoder struct {
+	name   string
+	prefix []byte
+	decode func(key, value []byte) (interface{}, error)
+}
+
+func bareDecoder(name string, prefix string, prototype func() interface{}) valueDecoder {
+	return valueDecoder{name, []byte(prefix), func(_, value []byte) (interface{}, error) {
+		ptr := prototype()
+		err := app.Codec.UnmarshalBinaryBare(value, ptr)
+		return ptr, err
+	}}
+}
+
+func lengthPrefixedDecoder(name string, prefix string, prototype func() interface{}) valueDecoder {
+	return valueDecoder{name, []byte(prefix), func(_, value []byte) (interface{}, error) {
+		ptr := prototype()
+		err := app.Codec.UnmarshalBinaryLengthPrefixed(value, ptr)
+		return ptr, err
+	}}
+}
+
+// valueDecoders are the decoders of the values of the stores by store name,
+// the first decoder whose prefix matches the key is used.
+var valueDecoders = map[string][]valueDecoder{
+	common.AccountStoreName: {
+		bareDecoder("account", "account:", func() interface{} { return new(sdk.Account) }),
+		lengthPrefixedDecoder("account_number", "globalAccountNumber", func() interface{} { return new(int64) }),
+	},
+	common.TokenStoreName: {
+		bareDecoder("token", "", func() interface{} { return new(nodetypes.IToken) }),
+	},
+	common.PairStoreName: {
+		bareDecoder("trading_pair", "", func() interface{} { return new(dextypes.TradingPair) }),
+	},
+	common.GovStoreName: {
+		lengthPrefixedDecoder("proposal", "proposals:", func() interface{} { return new(gov.Proposal) }),
+		lengthPrefixedDecoder("deposit", "deposits:", func() interface{} { return new(gov.Deposit) }),
+		lengthPrefixedDecoder("vote", "votes:", func() interface{} { return new(gov.Vote) }),
+		lengthPrefixedDecoder("proposal_id", "newProposalID", func() interface{} { return new(int64) }),
+		lengthPrefixedDecoder("proposal_queue", "activeProposalQueue", func() interface{} { return new(gov.ProposalQueue) }),
+		lengthPrefixedDecoder("proposal_queue", "inactiveProposalQueue", func() interface{} { return new(gov.ProposalQueue) }),
+	},
+	common.StakeStoreName: {
+		{"pool", stakekeeper.PoolKey, func(_, value []byte) (interface{}, error) {
+			return staketypes.UnmarshalPool(app.Codec, value)
+		}},
+		{"validator", stakekeeper.ValidatorsKey, func(_, value []byte) (interface{}, error) {
+			return staketypes.UnmarshalValidator(app.Codec, value)
+		}},
+		{"delegation", stakekeeper.DelegationKey, func(key, value []byte) (interface{}, error) {
+			return staketypes.UnmarshalDelegation(app.Codec, key, value)
+		}},
+		{"unbonding_delegation", stakekeeper.UnbondingDelegationKey, func(key, value []byte) (interface{}, error) {
+			return staketypes.UnmarshalUBD(app.Codec, key, value)
+		}},
+		{"redelegation", stakekeeper.RedelegationKey, func(key, value []byte) (interface{}, error) {
+			return staketypes.UnmarshalRED(app.Codec, key, value)
+		}},
+	},
+	common.StakeRewardStoreName: {
+		{"rewards", stakekeeper.RewardBatchKey, func(_, value []byte) (rewards interface{}, err error) {
+			// the rewards are decoded with a panic on error
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%v", r)
+				}
+			}()
+			return staketypes.MustUnmarshalRewards(app.Codec, value), nil
+		}},
+	},
+}
+
+// inspectEntry decodes the value of key in the store name with the first
+// decoder of the store matching the key. The values of unknown types are
+// returned as JSON when they are JSON objects, arrays or strings, as the
+// params are, and in hex otherwise.
+func inspectEntry(name string, key, value []byte) *types.InspectedEntry {
+	entry := &types.InspectedEntry{Key: key}
+	for _, decoder := range valueDecoders[name] {
+		if !bytes.HasPrefix(key, decoder.prefix) {
+			continue
+		}
+		decoded, err := decoder.decode(key, value)
+		if err != nil {
+			break
+		}
+		if entry.Value, err = app.Codec.MarshalJSON(decoded); err != nil {
+			break
+		}
+		entry.Type = decoder.name
+		return entry
+	}
+
+	if trimmed := bytes.TrimSpace(value); len(trimmed) > 0 && strings.ContainsRune(`{["`, rune(trimmed[0])) && json.Valid(trimmed) {
+		entry.Type = "json"
+		entry.Value = trimmed
+		return entry
+	}
+	entry.Hex = value
+	return entry
+}
+
+// parseKeyPrefix parses a hex prefix, or a bech32 address whose prefix is the
+// key of the account in the account store and the address bytes in the other
+// stores.
+func parseKeyPrefix(name string, prefix string) ([]byte, error) {
+	if addr, err := sdk.AccAddressFromBech32(prefix); err == nil {
+		if name == common.AccountStoreName {
+			return auth.AddressStoreKey(addr), nil
+		}
+		return addr, nil
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(prefix, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix %q, expected hex or bech32", prefix)
+	}
+	return decoded, nil
+}
+
+// InspectStore returns the entries of the store name under prefix, at most
+// limit entries when limit is positive.
+func InspectStore(app *app.BNBBeaconChain, name string, prefix []byte, limit int) (*types.InspectedStore, error) {
+	key, ok := common.StoreKeyNameMap[name]
+	if !ok {
+		return nil, fmt.Errorf("store %s is not mounted", name)
+	}
+	kvStore, ok := app.GetCommitMultiStore().GetCommitStore(key).(sdk.KVStore)
+	if !ok || kvStore.GetStoreType() != sdk.StoreTypeIAVL {
+		return nil, fmt.Errorf("store %s is not an IAVL store", name)
+	}
+
+	inspected := &types.InspectedStore{
+		Store:   name,
+		Prefix:  prefix,
+		Entries: make([]*types.InspectedEntry, 0),
+	}
+	iter := sdk.KVStorePrefixIterator(kvStore, prefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if limit > 0 && len(inspected.Entries) == limit {
+			inspected.More = true
+			break
+		}
+		inspected.Entries = append(inspected.Entries, inspectEntry(name, iter.Key(), iter.Value()))
+	}
+	return inspected, nil
+}
+
+// InspectCmd prints the keys and values of a store.
+func InspectCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Print the keys and values of a store of the multistore",
+		Long: `Print the keys and values of the IAVL store --store under the key --prefix, at
+the last block height. The application DB is opened read only.
+
+--prefix is hex, or a bech32 address: the key of the account in the acc store,
+and the address bytes in the other stores. The values of the accounts, tokens,
+trading pairs, proposals, deposits, votes and staking state are decoded with
+the codec of the app and printed in amino JSON, the values stored in JSON are
+printed as is, and the other values in hex. At most --limit entries are
+printed, all of them when --limit is 0.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := viper.GetString(flagStore)
+			if name == "" {
+				return fmt.Errorf("--%s should be set", flagStore)
+			}
+			prefix, err := parseKeyPrefix(name, viper.GetString(flagPrefix))
+			if err != nil {
+				return err
+			}
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			inspected, err := InspectStore(dapp, name, prefix, viper.GetInt(flagLimit))
+			if err != nil {
+				return err
+			}
+			trace("inspect", name, "entries", len(inspected.Entries))
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "\t")
+			return encoder.Encode(inspected)
+		},
+	}
+	cmd.Flags().String(flagStore, "", "name of the store, e.g. acc, tokens, gov or stake")
+	cmd.Flags().String(flagPrefix, "", "key prefix in hex, or a bech32 address")
+	cmd.Flags().Int(flagLimit, 100, "maximum number of entries, 0 for all")
+	return cmd
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	sm "github.com/tendermint/tendermint/state"
+	tmstore "github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/paramHub"
+
+	"github.com/bnb-chain/node/app"
+	nodetypes "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex"
+	"github.com/bnb-chain/node/plugins/tokens"
+	"github.com/bnb-chain/node/wire"
+)
+
+// newTestArchive writes the data of a chain at height 1 whose genesis holds an
+// account of addr owning BNB, ABC-000 and XYZ-000 to home, and sets --home to
+// it.
+func newTestArchive(t *testing.T, home string, addr sdk.AccAddress) {
+	viper.Set("home", home)
+	t.Cleanup(func() { viper.Set("home", "") })
+	dataDir := filepath.Join(home, "data")
+
+	db, err := dbm.NewGoLevelDB("application", dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every app registers its upgrade begin blockers in the upgrade manager of
+	// the process, which would run those of the previous apps too
+	sdk.UpgradeMgr.Reset()
+	dapp := app.NewBNBBeaconChain(log.NewNopLogger(), db, io.Discard)
+	acc := &nodetypes.AppAccount{BaseAccount: auth.BaseAccount{Address: addr}}
+	genesis, err := wire.MarshalJSONIndent(dapp.Codec, app.GenesisState{
+		Tokens: []tokens.GenesisToken{
+			{Name: "BNB", Symbol: "BNB", TotalSupply: 100000000e8, Owner: addr},
+			{Name: "ABC", Symbol: "ABC-000", TotalSupply: 100e8, Owner: addr},
+			{Name: "XYZ", Symbol: "XYZ-000", TotalSupply: 200e8, Owner: addr},
+		},
+		Accounts:     []app.GenesisAccount{app.NewGenesisAccount(acc, nil)},
+		DexGenesis:   dex.DefaultGenesis,
+		ParamGenesis: paramHub.DefaultGenesisState,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dapp.InitChain(abci.RequestInitChain{AppStateBytes: genesis})
+	dapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	dapp.EndBlock(abci.RequestEndBlock{Height: 1})
+	dapp.Commit()
+	db.Close()
+
+	// the app loads the last block and replays its orders when it starts
+	blockDB, err := dbm.NewGoLevelDB("blockstore", dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := tmtypes.MakeBlock(1, nil, &tmtypes.Commit{}, nil)
+	tmstore.NewBlockStore(blockDB).SaveBlock(block, block.MakePartSet(tmtypes.BlockPartSizeBytes), &tmtypes.Commit{})
+	blockDB.Close()
+	stateDB, err := dbm.NewGoLevelDB("state", dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := sm.NewABCIResponses(block)
+	responses.EndBlock = &abci.ResponseEndBlock{}
+	sm.SaveABCIResponses(stateDB, 1, responses)
+	stateDB.Close()
+}
+
+// readDir returns the content of the files in dir by name.
+func readDir(t *testing.T, dir string) map[string][]byte {
+	files := make(map[string][]byte)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = content
+	}
+	return files
+}
+
+func TestInspectReadOnlyApp(t *testing.T) {
+	home := t.TempDir()
+	addr := make(sdk.AccAddress, 20)
+	addr[0] = 1
+	newTestArchive(t, home, addr)
+	appDir := filepath.Join(home, "data", "application.db")
+	before := readDir(t, appDir)
+
+	dapp, db, err := openReadOnlyApp(server.NewDefaultContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dapp.LastBlockHeight() != 1 {
+		t.Fatalf("height %d, want 1", dapp.LastBlockHeight())
+	}
+	prefix, err := parseKeyPrefix("acc", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	inspected, err := InspectStore(dapp, "acc", prefix, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inspected.Entries) != 1 {
+		t.Fatalf("%d entries of the account in the acc store", len(inspected.Entries))
+	}
+	if entry := inspected.Entries[0]; entry.Type != "account" || !bytes.Contains(entry.Value, []byte(addr.String())) || entry.Hex != nil {
+		t.Errorf("unexpected entry of the account %+v", entry)
+	}
+	if err := tryWrite(db); err == nil {
+		t.Fatal("application DB is writable")
+	}
+	db.Close()
+
+	after := readDir(t, appDir)
+	if len(after) != len(before) {
+		t.Fatalf("application DB has %d files, had %d", len(after), len(before))
+	}
+	for name, content := range before {
+		if !bytes.Equal(after[name], content) {
+			t.Errorf("%s of the application DB changed", name)
+		}
+	}
+}
+
+func TestInspectEntryFallback(t *testing.T) {
+	// a key no decoder of the store matches
+	entry := inspectEntry("acc", []byte("unknown"), []byte{0x01, 0x02})
+	if entry.Type != "" || entry.Value != nil || !bytes.Equal(entry.Hex, []byte{0x01, 0x02}) {
+		t.Errorf("unexpected entry of an unknown key %+v", entry)
+	}
+	// a value the decoder of the prefix cannot decode
+	entry = inspectEntry("acc", []byte("account:x"), []byte{0xff})
+	if entry.Type != "" || !bytes.Equal(entry.Hex, []byte{0xff}) {
+		t.Errorf("unexpected entry of an undecodable account %+v", entry)
+	}
+	// a JSON value of a store without decoders
+	entry = inspectEntry("params", []byte("key"), []byte(` {"a":1}`))
+	if entry.Type != "json" || string(entry.Value) != `{"a":1}` || entry.Hex != nil {
+		t.Errorf("unexpected entry of a JSON value %+v", entry)
+	}
+}
+
+// tryWrite writes a key to db and returns the panic of the failed write.
+func tryWrite(db dbm.DB) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	db.Set([]byte("key"), []byte("value"))
+	return nil
+}
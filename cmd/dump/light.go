@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/lite"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+
+	"github.com/bnb-chain/node-dump/light"
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagCheckpoint = "checkpoint"
+	flagExport     = "export"
+
+	lightBundleFile = "light.json"
+)
+
+// FullCommit returns the signed header of height with the validator sets of
+// height and of the next height.
+func (r *blockReader) FullCommit(height int64) (lite.FullCommit, error) {
+	meta := r.blockStore.LoadBlockMeta(height)
+	if meta == nil {
+		return lite.FullCommit{}, fmt.Errorf("block %d is not in the block store", height)
+	}
+	commit, err := r.Commit(height)
+	if err != nil {
+		return lite.FullCommit{}, err
+	}
+	validators, err := r.Validators(height)
+	if err != nil {
+		return lite.FullCommit{}, err
+	}
+	nextValidators, err := r.Validators(height + 1)
+	if err != nil {
+		return lite.FullCommit{}, err
+	}
+	header := meta.Header
+	return lite.NewFullCommit(tmtypes.SignedHeader{Header: &header, Commit: commit}, validators, nextValidators), nil
+}
+
+// LightBundle builds the bundle proving commitID from the trusted validators.
+// The app hash of a height is in the header of the next height, which must be
+// in the block store.
+func (r *blockReader) LightBundle(trusted *types.TrustedValidators, commitID sdk.CommitID) (*types.LightBundle, error) {
+	final := commitID.Version + 1
+	if final > r.blockStore.Height() {
+		return nil, fmt.Errorf("header %d carrying the app hash of height %d is not in the block store of height %d",
+			final, commitID.Version, r.blockStore.Height())
+	}
+	if trusted.Height < 1 || trusted.Height > final {
+		return nil, fmt.Errorf("trusted height %d is not between 1 and %d", trusted.Height, final)
+	}
+
+	bundle := &types.LightBundle{
+		ChainID:     trusted.ChainID,
+		Trusted:     *trusted,
+		Transitions: make([]lite.FullCommit, 0),
+		CommitID:    commitID,
+	}
+	for height := trusted.Height; height < final; height++ {
+		meta := r.blockStore.LoadBlockMeta(height)
+		if meta == nil {
+			return nil, fmt.Errorf("block %d is not in the block store", height)
+		}
+		if bytes.Equal(meta.Header.ValidatorsHash, meta.Header.NextValidatorsHash) {
+			continue
+		}
+		fc, err := r.FullCommit(height)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Transitions = append(bundle.Transitions, fc)
+		trace("validator set change", "height", height+1)
+	}
+	fc, err := r.FullCommit(final)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Final = fc
+	return bundle, nil
+}
+
+// loadTrustedValidators reads the trusted validators of a checkpoint file, or
+// the validators of the genesis file of home when checkpoint is empty.
+func loadTrustedValidators(home string, checkpoint string) (*types.TrustedValidators, error) {
+	if checkpoint != "" {
+		data, err := os.ReadFile(checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		var trusted types.TrustedValidators
+		if err := app.Codec.UnmarshalJSON(data, &trusted); err != nil {
+			return nil, fmt.Errorf("decode checkpoint %s: %w", checkpoint, err)
+		}
+		if trusted.Validators.IsNilOrEmpty() {
+			return nil, fmt.Errorf("no validators in checkpoint %s", checkpoint)
+		}
+		return &trusted, nil
+	}
+
+	genesis, err := tmtypes.GenesisDocFromFile(filepath.Join(home, "config", "genesis.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(genesis.Validators) == 0 {
+		return nil, fmt.Errorf("no validators in the genesis file, --%s should be set", flagCheckpoint)
+	}
+	validators := make([]*tmtypes.Validator, 0, len(genesis.Validators))
+	for _, validator := range genesis.Validators {
+		validators = append(validators, tmtypes.NewValidator(validator.PubKey, validator.Power))
+	}
+	return &types.TrustedValidators{
+		ChainID:    genesis.ChainID,
+		Height:     1,
+		Validators: tmtypes.NewValidatorSet(validators),
+	}, nil
+}
+
+// LightBundleCmd builds the light client bundle of an export.
+func LightBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "light-bundle <path>",
+		Short: "Build the light client bundle proving the commit hash of an export",
+		Long: `Build the light client bundle proving the commit hash of the export in <path>.
+
+The block store and the state DB of --home are opened read only. Starting from
+the validators of the genesis file, or of --checkpoint, the bundle records the
+signed headers of the heights after which the validator set changes, and the
+signed header carrying the app hash of the height of the export. The app hash of
+a height is in the header of the next height, which must be in the block store.
+The bundle is verified and written to <path>/light.json.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<path> should be set")
+			}
+			state, err := loadExportedState(args[0])
+			if err != nil {
+				return err
+			}
+			home := viper.GetString("home")
+			trusted, err := loadTrustedValidators(home, viper.GetString(flagCheckpoint))
+			if err != nil {
+				return err
+			}
+			if trusted.ChainID != state.ChainID {
+				return fmt.Errorf("trusted validators of chain %s, export of chain %s", trusted.ChainID, state.ChainID)
+			}
+
+			reader, err := openBlockReader(home)
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+			bundle, err := reader.LightBundle(trusted, state.CommitID)
+			if err != nil {
+				return err
+			}
+			if err := light.VerifyBundle(bundle); err != nil {
+				return err
+			}
+
+			data, err := app.Codec.MarshalJSONIndent(bundle, "", "\t")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path.Join(args[0], lightBundleFile), data, 0644); err != nil {
+				return err
+			}
+			fmt.Println("Light client bundle written, validator set changes:", len(bundle.Transitions),
+				"final header:", bundle.Final.Height())
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagCheckpoint, "", "file of the trusted validators, the genesis validators when not set")
+	return cmd
+}
+
+// VerifyLightCmd verifies a light client bundle offline.
+func VerifyLightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-light <bundle>",
+		Short: "Verify a light client bundle offline",
+		Long: `Verify the light client bundle proving the commit hash of an export, without
+the node data.
+
+The signed headers of the bundle are verified from its trusted validators with
+the light client of Tendermint, and the app hash of the final header is checked
+against the commit hash of the bundle. The trusted validators should be pinned
+with --checkpoint, otherwise the trusted validators of the bundle are used and
+their hash is printed to be compared out of band. With --export, the commit of
+the bundle is checked against the base.json of the export.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<bundle> should be set")
+			}
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var bundle types.LightBundle
+			if err := app.Codec.UnmarshalJSON(data, &bundle); err != nil {
+				return fmt.Errorf("decode bundle %s: %w", args[0], err)
+			}
+
+			if checkpoint := viper.GetString(flagCheckpoint); checkpoint != "" {
+				trusted, err := loadTrustedValidators("", checkpoint)
+				if err != nil {
+					return err
+				}
+				if trusted.ChainID != bundle.Trusted.ChainID || trusted.Height != bundle.Trusted.Height ||
+					!bytes.Equal(trusted.Validators.Hash(), bundle.Trusted.Validators.Hash()) {
+					return fmt.Errorf("trusted validators of the bundle do not match the checkpoint")
+				}
+			} else {
+				fmt.Printf("Trusted validators of the bundle are not pinned, height: %d hash: %X\n",
+					bundle.Trusted.Height, bundle.Trusted.Validators.Hash())
+			}
+			if export := viper.GetString(flagExport); export != "" {
+				state, err := loadExportedState(export)
+				if err != nil {
+					return err
+				}
+				if state.ChainID != bundle.ChainID || state.CommitID.Version != bundle.CommitID.Version ||
+					!bytes.Equal(state.CommitID.Hash, bundle.CommitID.Hash) {
+					return fmt.Errorf("commit of the bundle does not match the export")
+				}
+			}
+
+			if err := light.VerifyBundle(&bundle); err != nil {
+				return err
+			}
+			fmt.Printf("Commit hash %X of height %d verified\n", bundle.CommitID.Hash, bundle.CommitID.Version)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagCheckpoint, "", "file of the trusted validators")
+	cmd.Flags().String(flagExport, "", "directory of the export containing base.json")
+	return cmd
+}
@@ -4,16 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math/big"
 	"os"
 	"path"
 	"path/filepath"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	tmCrypto "github.com/tendermint/tendermint/crypto"
@@ -27,8 +25,7 @@ import (
 	"github.com/bnb-chain/node/app"
 	nodetypes "github.com/bnb-chain/node/common/types"
 
-	mt "github.com/txaty/go-merkletree"
-
+	"github.com/bnb-chain/node-dump/proof"
 	"github.com/bnb-chain/node-dump/types"
 	"github.com/bnb-chain/node-dump/util"
 )
@@ -41,156 +38,84 @@ const (
 	flagTraceStore = "trace-store"
 )
 
-func NewHashFunc(data []byte) ([]byte, error) {
-	return crypto.Keccak256(data), nil
-}
-
-type leafNode struct {
-	Address sdk.AccAddress `json:"address"`
-	Coin    sdk.Coin       `json:"coin"`
-}
-
-// Serialize implements merkle tree data Serialize method.
-func (node *leafNode) Serialize() ([]byte, error) {
-	var symbol [32]byte
-	copy(symbol[:], node.Coin.Denom)
-	return crypto.Keccak256(
-		node.Address.Bytes(),
-		symbol[:],
-		big.NewInt(node.Coin.Amount).FillBytes(make([]byte, 32)),
-	), nil
-}
-
-func (node *leafNode) Print() string {
-	buf, _ := node.Serialize()
-
-	return "0x" + common.Bytes2Hex(crypto.Keccak256(buf))
-}
-
 // ExportAccountsBalanceWithProof exports blockchain world state to json.
-func ExportAccountsBalanceWithProof(app *app.BNBBeaconChain, outputPath string) (err error) {
+func ExportAccountsBalanceWithProof(app *app.BNBBeaconChain, outputPath string, excluded *excludedRegistry,
+	checkpointInterval time.Duration, resume bool) (err error) {
 	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
 
-	// Escrow Accounts
-	escrowAccs := make(map[string]struct{})
-	// bnb prefix address: bnb1vu5max8wqn997ayhrrys0drpll2rlz4dh39s3h
-	// tbnb prefix address: tbnb1vu5max8wqn997ayhrrys0drpll2rlz4deyv53x
-	depositedCoinsAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainDepositedCoins")))
-	// bnb prefix address: bnb1j725qk29cv4kwpers4addy9x93ukhw7czfkjaj
-	// tbnb prefix address: tbnb1j725qk29cv4kwpers4addy9x93ukhw7cvulkar
-	delegationAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainStakeDelegation")))
-	// bnb prefix address: bnb1v8vkkymvhe2sf7gd2092ujc6hweta38xadu2pj
-	// tbnb prefix address: tbnb1v8vkkymvhe2sf7gd2092ujc6hweta38xnc4wpr
-	pegAccount := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainPegAccount")))
-	// bnb prefix address: bnb1wxeplyw7x8aahy93w96yhwm7xcq3ke4f8ge93u
-	// tbnb prefix address: tbnb1wxeplyw7x8aahy93w96yhwm7xcq3ke4ffasp3d
-	atomicSwapCoinsAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainAtomicSwapCoins")))
-	// bnb prefix address: bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq
-	// tbnb prefix address: tbnb1hn8ym9xht925jkncjpf7lhjnax6z8nv2mu9wy3
-	timeLockCoinsAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainTimeLockCoins")))
-	// nil address
-	emptyAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte(nil)))
-	// 0x0000... address
-	zeroAccAddr, err := sdk.AccAddressFromHex("0000000000000000000000000000000000000000")
+	excluded.Trace()
+
+	// iterate to get the accounts, continue from the checkpoint when resuming
+	checkpoint, accounts, leaves, err := openExportCheckpoint(path.Join(outputPath, checkpointDirName), checkpointInterval, resume,
+		app.CheckState.Ctx.ChainID(), app.LastBlockHeight(), app.LastCommitID(), excluded.Accounts())
 	if err != nil {
 		return err
 	}
-	trace("escrow accounts",
-		"depositedCoinsAccAddr:", depositedCoinsAccAddr.String(),
-		"delegationAccAddr:", delegationAccAddr.String(),
-		"pegAccount:", pegAccount.String(),
-		"atomicSwapCoinsAccAddr:", atomicSwapCoinsAccAddr.String(),
-		"timeLockCoinsAccAddr:", timeLockCoinsAccAddr.String(),
-		"emptyAccAddr:", emptyAccAddr.String(),
-		"zeroAccAddr:", zeroAccAddr.String(),
-	)
-	escrowAccs[depositedCoinsAccAddr.String()] = struct{}{}
-	escrowAccs[delegationAccAddr.String()] = struct{}{}
-	escrowAccs[pegAccount.String()] = struct{}{}
-	escrowAccs[atomicSwapCoinsAccAddr.String()] = struct{}{}
-	escrowAccs[timeLockCoinsAccAddr.String()] = struct{}{}
-	escrowAccs[emptyAccAddr.String()] = struct{}{}
-	escrowAccs[zeroAccAddr.String()] = struct{}{}
-
-	// iterate to get the accounts
-	accounts := []*types.ExportedAccount{}
-	mtData := []mt.DataBlock{}
-
-	appendAccount := func(acc sdk.Account) (stop bool) {
+	defer checkpoint.Close()
+	if resume {
+		trace("resume from checkpoint", "accounts", len(accounts), "leaves", len(leaves))
+	}
+
+	appendAccount := func(key []byte, acc sdk.Account) (stop bool) {
 		namedAcc := acc.(nodetypes.NamedAccount)
 		addr := namedAcc.GetAddress()
-		if _, exist := escrowAccs[addr.String()]; exist {
-			trace("skip escrow account:", addr.String())
-			return false
+		if account, exist := excluded.Lookup(addr); exist {
+			trace("skip excluded account:", account.Label, addr.String())
+			err = checkpoint.Append(key, nil, nil)
+			return err != nil
 		}
 
-		coins := namedAcc.GetCoins()
-		frozenCoins := namedAcc.GetFrozenCoins()
-		lockedCoins := namedAcc.GetLockedCoins()
+		// the leaves stay on the total
+		account := exportAccount(namedAcc)
+		accounts = append(accounts, account)
 
-		allCoins := coins.Plus(frozenCoins)
-		allCoins = allCoins.Plus(lockedCoins)
-
-		account := types.ExportedAccount{
-			Address:       addr,
-			AccountNumber: namedAcc.GetAccountNumber(),
-			Coins:         allCoins.Sort(),
-		}
-		accounts = append(accounts, &account)
-
-		for index := range allCoins {
-			if allCoins[index].Amount > 0 {
-				mtData = append(mtData, &leafNode{
-					Address: addr,
-					Coin:    allCoins[index],
-				})
+		leafStart := len(leaves)
+		for index := range account.Coins {
+			if account.Coins[index].Amount > 0 {
+				leaves = append(leaves, proof.NewLeaf(addr, account.Coins[index]))
 			}
 		}
 
-		trace("address", acc.GetAddress(), "account:", account)
+		trace("address", acc.GetAddress(), "account:", *account)
 
-		return false
+		err = checkpoint.Append(key, account, leaves[leafStart:])
+		return err != nil
 	}
 
 	trace("iterate accounts...")
-	app.AccountKeeper.IterateAccounts(ctx, appendAccount)
-
-	trace("make merkle tree...")
-	// create a Merkle Tree config and set parallel run parameters
-	config := &mt.Config{
-		HashFunc:           NewHashFunc,
-		RunInParallel:      true,
-		SortSiblingPairs:   true,
-		DisableLeafHashing: true,
+	if iterErr := iterateAccountsFrom(ctx, app, checkpoint.LastKey(), appendAccount); iterErr != nil {
+		return iterErr
+	}
+	if err != nil {
+		return err
+	}
+	err = checkpoint.Save()
+	if err != nil {
+		return err
 	}
 
-	tree, err := mt.New(config, mtData)
+	summary := summarizeAccounts(accounts)
+	trace("holders", summary.Holders,
+		"with public key", summary.HoldersWithPubKey,
+		"without public key", summary.HoldersWithoutPubKey)
+
+	trace("make merkle tree...")
+	tree, err := proof.NewTree(leaves)
 	if err != nil {
 		return err
 	}
 
 	trace("make proofs...")
-	proofs := tree.Proofs
 	maxProofLength := 0
-	exportedProof := make([]*types.ExportedProof, 0, len(proofs))
-	trace("proofs length", len(proofs))
-	for i := 0; i < len(mtData); i++ {
-		proof := proofs[i]
-		nProof := make([]string, 0, len(proof.Siblings))
-		for j := 0; j < len(proof.Siblings); j++ {
-			nProof = append(nProof, "0x"+common.Bytes2Hex(proof.Siblings[j]))
-		}
-
-		leaf := mtData[i].(*leafNode)
-		exportedProof = append(exportedProof, &types.ExportedProof{
-			Address: leaf.Address,
-			Coin:    leaf.Coin,
-			Proof:   nProof,
-		})
-		if proofLength := len(proof.Siblings); proofLength > maxProofLength {
+	exportedProof := make([]*types.ExportedProof, 0, len(leaves))
+	trace("proofs length", len(leaves))
+	for i, leaf := range leaves {
+		exported := tree.ExportedProof(i)
+		exportedProof = append(exportedProof, exported)
+		if proofLength := len(exported.Proof); proofLength > maxProofLength {
 			maxProofLength = proofLength
 		}
-		trace("address:", leaf.Address.String(), "proof:", nProof, "leaf:", leaf.Print())
+		trace("address:", leaf.Address.String(), "proof:", exported.Proof, "leaf:", leaf.Print())
 	}
 	trace("max proof length:", maxProofLength)
 
@@ -199,14 +124,16 @@ func ExportAccountsBalanceWithProof(app *app.BNBBeaconChain, outputPath string)
 		BlockHeight: app.LastBlockHeight(),
 		CommitID:    app.LastCommitID(),
 		Accounts:    accounts,
-		StateRoot:   "0x" + common.Bytes2Hex(tree.Root),
+		StateRoot:   tree.RootHex(),
+		Summary:     summary,
+		Excluded:    excluded.Accounts(),
 		Proofs:      exportedProof,
 	}
 
 	trace("write to file...")
 
 	// write the state to the file
-	baseFile, err := os.OpenFile(path.Join(outputPath, "base.json"), os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	baseFile, err := os.OpenFile(path.Join(outputPath, "base.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
 	if err != nil {
 		return err
 	}
@@ -216,8 +143,21 @@ func ExportAccountsBalanceWithProof(app *app.BNBBeaconChain, outputPath string)
 		return err
 	}
 
+	// write the balances of the excluded accounts to the file
+	excludedBalanceFile, err := os.OpenFile(path.Join(outputPath, excludedFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer excludedBalanceFile.Close()
+	err = writeJSONFile(excludedBalanceFile, excludedBalances(excluded, func(addr sdk.AccAddress) sdk.Account {
+		return app.AccountKeeper.GetAccount(ctx, addr)
+	}))
+	if err != nil {
+		return err
+	}
+
 	// write the accounts to the file
-	accountFile, err := os.OpenFile(path.Join(outputPath, "accounts.json"), os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	accountFile, err := os.OpenFile(path.Join(outputPath, "accounts.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
 	if err != nil {
 		return err
 	}
@@ -242,7 +182,7 @@ func ExportAccountsBalanceWithProof(app *app.BNBBeaconChain, outputPath string)
 	}
 
 	// write the proofs to the file
-	proofFile, err := os.OpenFile(path.Join(outputPath, "proofs.json"), os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	proofFile, err := os.OpenFile(path.Join(outputPath, "proofs.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
 	if err != nil {
 		return err
 	}
@@ -253,7 +193,7 @@ func ExportAccountsBalanceWithProof(app *app.BNBBeaconChain, outputPath string)
 			if err != nil {
 				return err
 			}
-			if i < len(proofs)-1 {
+			if i < len(exportedProof)-1 {
 				_, err = proofFile.WriteString(`,`)
 				if err != nil {
 					return err
@@ -272,7 +212,10 @@ func ExportAccountsBalanceWithProof(app *app.BNBBeaconChain, outputPath string)
 func writeJSONFile(file *os.File, data interface{}) error {
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "\t")
-	return encoder.Encode(data)
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+	return file.Sync()
 }
 
 func writeJSONFileInStream(file *os.File, marshal func(*json.Encoder) error) error {
@@ -287,12 +230,12 @@ func writeJSONFileInStream(file *os.File, marshal func(*json.Encoder) error) err
 	if _, err := file.WriteString(`]`); err != nil {
 		return err
 	}
-	return nil
+	return file.Sync()
 }
 
 // ExportCmd dumps app state to JSON.
 func ExportCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "export <path>",
 		Short: "Export state to JSON",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -320,6 +263,26 @@ func ExportCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 				return nil
 			}
 
+			var signKey tmCrypto.PrivKey
+			if signKeyFile := viper.GetString(flagSignKey); signKeyFile != "" {
+				signKey, err = loadSigningKey(signKeyFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			excluded, err := loadExcludedRegistry(viper.GetString(flagExcludeFile))
+			if err != nil {
+				return err
+			}
+
+			resume := viper.GetBool(flagResume)
+			output, err := newExportOutput(args[0], viper.GetBool(flagForce), resume)
+			if err != nil {
+				return err
+			}
+			defer output.Abort()
+
 			db, err := openDB(home)
 			if err != nil {
 				return err
@@ -330,7 +293,19 @@ func ExportCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 			}
 
 			dapp := app.NewBNBBeaconChain(ctx.Logger, db, traceWriter)
-			err = ExportAccountsBalanceWithProof(dapp, args[0])
+			err = ExportAccountsBalanceWithProof(dapp, output.staging, excluded, viper.GetDuration(flagCheckpointInterval), resume)
+			if err != nil {
+				return err
+			}
+
+			trace("write manifest...")
+			err = WriteExportManifest(output.staging, signKey)
+			if err != nil {
+				return err
+			}
+
+			trace("commit export...", output.dir)
+			err = output.Commit()
 			if err != nil {
 				return err
 			}
@@ -338,9 +313,15 @@ func ExportCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().String(flagSignKey, "", "ed25519 or secp256k1 private key file to sign the export manifest with")
+	cmd.Flags().Bool(flagForce, false, "replace the files of the existing export in <path>")
+	cmd.Flags().Bool(flagResume, false, "resume an interrupted export from its last checkpoint")
+	cmd.Flags().Duration(flagCheckpointInterval, 5*time.Minute, "interval between the checkpoints of the account iteration")
+	cmd.Flags().String(flagExcludeFile, "", "YAML or JSON file of accounts to exclude in addition to the escrow accounts")
+	return cmd
 }
 
-func VerifyProofsFromDatabase(app *app.BNBBeaconChain, proofPath string) (err error) {
+func VerifyProofsFromDatabase(app *app.BNBBeaconChain, proofPath string, excludeFile string) (err error) {
 	// load exported state
 	stateFile, err := os.Open(path.Join(proofPath, "base.json"))
 	if err != nil {
@@ -368,9 +349,9 @@ func VerifyProofsFromDatabase(app *app.BNBBeaconChain, proofPath string) (err er
 				errChan <- data.Error
 				return
 			}
-			proof := data.Data.(*types.ExportedProof)
-			index := proof.Address.String() + ":" + proof.Coin.Denom
-			proofs[index] = proof
+			exported := data.Data.(*types.ExportedProof)
+			index := exported.Address.String() + ":" + exported.Coin.Denom
+			proofs[index] = exported
 		}
 		errChan <- nil
 	}()
@@ -382,46 +363,21 @@ func VerifyProofsFromDatabase(app *app.BNBBeaconChain, proofPath string) (err er
 
 	// prepare context
 	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
-	// Escrow Accounts
-	escrowAccs := make(map[string]struct{})
-	// bnb prefix address: bnb1vu5max8wqn997ayhrrys0drpll2rlz4dh39s3h
-	// tbnb prefix address: tbnb1vu5max8wqn997ayhrrys0drpll2rlz4deyv53x
-	depositedCoinsAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainDepositedCoins")))
-	// bnb prefix address: bnb1j725qk29cv4kwpers4addy9x93ukhw7czfkjaj
-	// tbnb prefix address: tbnb1j725qk29cv4kwpers4addy9x93ukhw7cvulkar
-	delegationAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainStakeDelegation")))
-	// bnb prefix address: bnb1v8vkkymvhe2sf7gd2092ujc6hweta38xadu2pj
-	// tbnb prefix address: tbnb1v8vkkymvhe2sf7gd2092ujc6hweta38xnc4wpr
-	pegAccount := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainPegAccount")))
-	// bnb prefix address: bnb1wxeplyw7x8aahy93w96yhwm7xcq3ke4f8ge93u
-	// tbnb prefix address: tbnb1wxeplyw7x8aahy93w96yhwm7xcq3ke4ffasp3d
-	atomicSwapCoinsAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainAtomicSwapCoins")))
-	// bnb prefix address: bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq
-	// tbnb prefix address: tbnb1hn8ym9xht925jkncjpf7lhjnax6z8nv2mu9wy3
-	timeLockCoinsAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte("BinanceChainTimeLockCoins")))
-	// nil address
-	emptyAccAddr := sdk.AccAddress(tmCrypto.AddressHash([]byte(nil)))
-	// 0x0000... address
-	zeroAccAddr, err := sdk.AccAddressFromHex("0000000000000000000000000000000000000000")
+	// exclude the accounts recorded by the export, or the defaults for an
+	// export that did not record them
+	var excluded *excludedRegistry
+	if len(state.Excluded) > 0 {
+		if excludeFile != "" {
+			return fmt.Errorf("the export records its excluded accounts, --%s should not be set", flagExcludeFile)
+		}
+		excluded, err = newExcludedRegistry(state.Excluded)
+	} else {
+		excluded, err = loadExcludedRegistry(excludeFile)
+	}
 	if err != nil {
 		return err
 	}
-	trace("escrow accounts",
-		"depositedCoinsAccAddr:", depositedCoinsAccAddr.String(),
-		"delegationAccAddr:", delegationAccAddr.String(),
-		"pegAccount:", pegAccount.String(),
-		"atomicSwapCoinsAccAddr:", atomicSwapCoinsAccAddr.String(),
-		"timeLockCoinsAccAddr:", timeLockCoinsAccAddr.String(),
-		"emptyAccAddr:", emptyAccAddr.String(),
-		"zeroAccAddr:", zeroAccAddr.String(),
-	)
-	escrowAccs[depositedCoinsAccAddr.String()] = struct{}{}
-	escrowAccs[delegationAccAddr.String()] = struct{}{}
-	escrowAccs[pegAccount.String()] = struct{}{}
-	escrowAccs[atomicSwapCoinsAccAddr.String()] = struct{}{}
-	escrowAccs[timeLockCoinsAccAddr.String()] = struct{}{}
-	escrowAccs[emptyAccAddr.String()] = struct{}{}
-	escrowAccs[zeroAccAddr.String()] = struct{}{}
+	excluded.Trace()
 
 	// iterate to verify the accounts
 	count := 0
@@ -439,50 +395,32 @@ func VerifyProofsFromDatabase(app *app.BNBBeaconChain, proofPath string) (err er
 
 		namedAcc := acc.(nodetypes.NamedAccount)
 		addr := namedAcc.GetAddress()
-		if _, matched := escrowAccs[addr.String()]; matched {
-			trace("skip escrow account:", addr.String())
+		if account, matched := excluded.Lookup(addr); matched {
+			trace("skip excluded account:", account.Label, addr.String())
 			return false
 		}
 
-		coins := namedAcc.GetCoins()
-		frozenCoins := namedAcc.GetFrozenCoins()
-		lockedCoins := namedAcc.GetLockedCoins()
-
-		allCoins := coins.Plus(frozenCoins)
-		allCoins = allCoins.Plus(lockedCoins)
+		_, _, _, allCoins := accountBalances(namedAcc)
 
 		for _, coin := range allCoins {
 			if coin.Amount > 0 {
-				proof, exist := proofs[addr.String()+":"+coin.Denom]
+				exported, exist := proofs[addr.String()+":"+coin.Denom]
 				if !exist {
 					trace("proof not found", addr.String(), coin.Denom)
 					return true
 				}
 
-				if coin.Amount != proof.Coin.Amount {
+				if coin.Amount != exported.Coin.Amount {
 					trace("amount mismatch",
 						"address", addr.String(),
 						"symbol", coin.Denom,
 						"expected", coin.Amount,
-						"actual", proof.Coin.Amount)
+						"actual", exported.Coin.Amount)
 					return true
 				}
 
 				// verify merkle proof
-				leaf := &leafNode{
-					Address: addr,
-					Coin:    coin,
-				}
-				leafHash, err := leaf.Serialize()
-				if err != nil {
-					trace("merkle proof serialization failed",
-						"address", addr.String(),
-						"symbol", coin.Denom,
-						"amount", coin.Amount)
-					return true
-				}
-
-				if !util.VerifyMerkleProof(merkleRoot, util.MustDecodeHexArrayToBytes(proof.Proof), leafHash) {
+				if !proof.Verify(merkleRoot, util.MustDecodeHexArrayToBytes(exported.Proof), proof.NewLeaf(addr, coin)) {
 					trace("merkle proof verification failed",
 						"address", addr.String(),
 						"symbol", coin.Denom,
@@ -505,7 +443,7 @@ func VerifyProofsFromDatabase(app *app.BNBBeaconChain, proofPath string) (err er
 
 // VerificationCmd verify the proofs from database.
 func VerificationCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "verify <path>",
 		Short: "Verify the exported proofs from database",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -543,7 +481,7 @@ func VerificationCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 			}
 
 			dapp := app.NewBNBBeaconChain(ctx.Logger, db, traceWriter)
-			err = VerifyProofsFromDatabase(dapp, args[0])
+			err = VerifyProofsFromDatabase(dapp, args[0], viper.GetString(flagExcludeFile))
 			if err != nil {
 				return err
 			}
@@ -552,6 +490,8 @@ func VerificationCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().String(flagExcludeFile, "", "exclude file of an export that did not record its excluded accounts")
+	return cmd
 }
 
 func isEmptyState(home string) (bool, error) {
@@ -564,12 +504,45 @@ func isEmptyState(home string) (bool, error) {
 	return len(files) == 1 && files[0].Name() == "priv_validator_state.json", nil
 }
 
+// openReadOnlyApp opens the app of --home on its application DB opened read
+// only, for the commands that only query the archive. The app still opens the
+// block store and the state DB of --home while it starts, to load its last
+// block and replay the orders of the dex, so --home should not be the data of a
+// running node. The caller closes the returned DB.
+func openReadOnlyApp(ctx *server.Context) (*app.BNBBeaconChain, dbm.DB, error) {
+	home := viper.GetString("home")
+	emptyState, err := isEmptyState(home)
+	if err != nil {
+		return nil, nil, err
+	}
+	if emptyState {
+		return nil, nil, fmt.Errorf("state in %s is not initialized", home)
+	}
+
+	db, err := openReadOnlyDB(home, "application")
+	if err != nil {
+		return nil, nil, err
+	}
+	traceWriter, err := openTraceWriter(viper.GetString(flagTraceStore))
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return app.NewBNBBeaconChain(ctx.Logger, db, traceWriter), db, nil
+}
+
 func openDB(rootDir string) (dbm.DB, error) {
 	dataDir := filepath.Join(rootDir, "data")
 	db, err := dbm.NewGoLevelDB("application", dataDir)
 	return db, err
 }
 
+// openReadOnlyDB opens the leveldb name in the data directory of home without
+// writing to it.
+func openReadOnlyDB(home string, name string) (dbm.DB, error) {
+	return dbm.NewGoLevelDBWithOpts(name, filepath.Join(home, "data"), &opt.Options{ReadOnly: true})
+}
+
 func openTraceWriter(traceWriterFile string) (w io.Writer, err error) {
 	if traceWriterFile != "" {
 		w, err = os.OpenFile(
@@ -606,6 +579,27 @@ func main() {
 
 	rootCmd.AddCommand(ExportCmd(ctx.ToCosmosServerCtx(), cdc))
 	rootCmd.AddCommand(VerificationCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(ExportOrdersCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(ExportStakingCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(ExportGovCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(TxHistoryCmd())
+	rootCmd.AddCommand(ExportBlocksCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(LightBundleCmd())
+	rootCmd.AddCommand(VerifyLightCmd())
+	rootCmd.AddCommand(StoresCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(InspectCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(AccountCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(APIServerCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(RPCServerCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(StatsCmd())
+	rootCmd.AddCommand(FetchCmd())
+	rootCmd.AddCommand(ExtractCmd())
+	rootCmd.AddCommand(ManifestCmd())
+	rootCmd.AddCommand(VerifyManifestCmd())
+	rootCmd.AddCommand(TestVectorsCmd())
+	rootCmd.AddCommand(ClaimTxCmd())
+	rootCmd.AddCommand(SignClaimCmd())
+	rootCmd.AddCommand(VerifyClaimCmd())
 	rootCmd.PersistentFlags().BoolVar(&traceLog, "tracelog", false, "print out full stack trace on errors")
 	// prepare and add flags
 	executor := cli.PrepareBaseCmd(rootCmd, "BC", app.DefaultNodeHome)
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bnb-chain/node-dump/snapshot"
+)
+
+const (
+	flagManifest = "manifest"
+)
+
+// ManifestCmd generates the per-segment checksum manifest of a snapshot.
+func ManifestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest <path/manifest.json>",
+		Short: "Generate the size and SHA256 manifest of the snapshot segments",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<path/manifest.json> should be set")
+			}
+			network, err := snapshot.GetNetwork(viper.GetString(flagNetwork))
+			if err != nil {
+				return err
+			}
+
+			fetcher := snapshot.NewFetcher()
+			fetcher.Retries = viper.GetInt(flagRetries)
+			fetcher.Trace = trace
+			source, err := segmentSource(network, fetcher)
+			if err != nil {
+				return err
+			}
+
+			trace("hash segments...", "network", network.Name, "segments", source.Len())
+			manifest, err := snapshot.GenerateManifest(context.Background(), network, source, fetcher.Retries, fetcher.RetryDelay, trace)
+			if err != nil {
+				return err
+			}
+			err = snapshot.WriteManifest(args[0], manifest)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Manifest written:", args[0])
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagNetwork, "mainnet", "snapshot network, mainnet or testnet")
+	cmd.Flags().String(flagSegments, "", "directory of the downloaded segments, stream from the published links if empty")
+	cmd.Flags().Int(flagRetries, 5, "number of retries for each segment")
+	return cmd
+}
+
+// segmentSource returns the local segments when --segments is set, otherwise
+// the published links of the network.
+func segmentSource(network *snapshot.Network, fetcher *snapshot.Fetcher) (snapshot.Source, error) {
+	segmentDir := viper.GetString(flagSegments)
+	if segmentDir == "" {
+		return &snapshot.RemoteSource{Fetcher: fetcher, Links: network.Segments}, nil
+	}
+
+	paths := make([]string, 0, len(network.Segments))
+	for _, link := range network.Segments {
+		p, err := snapshot.SegmentPath(segmentDir, link)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return &snapshot.LocalSource{Paths: paths}, nil
+}
+
+// loadManifest loads the manifest given by --manifest, it returns nil if unset.
+func loadManifest() (*snapshot.Manifest, error) {
+	manifestPath := viper.GetString(flagManifest)
+	if manifestPath == "" {
+		return nil, nil
+	}
+	return snapshot.LoadManifest(manifestPath)
+}
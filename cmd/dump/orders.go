@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+	nodetypes "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex/order"
+	dexutils "github.com/bnb-chain/node/plugins/dex/utils"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// exportOrder returns the exported open order, the locked coin is the quote
+// notional of the unfilled quantity for a buy and the unfilled base quantity
+// for a sell.
+func exportOrder(info *order.OrderInfo) (*types.ExportedOrder, error) {
+	baseAsset, quoteAsset, err := dexutils.TradingPair2Assets(info.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	exported := &types.ExportedOrder{
+		ID:            info.Id,
+		Owner:         info.Sender,
+		Pair:          info.Symbol,
+		Price:         info.Price,
+		Quantity:      info.Quantity,
+		CumQty:        info.CumQty,
+		CreatedHeight: info.CreatedHeight,
+	}
+	switch info.Side {
+	case order.Side.BUY:
+		exported.Side = "BUY"
+		exported.Locked = sdk.NewCoin(quoteAsset,
+			dexutils.CalBigNotionalInt64(info.Price, info.Quantity)-dexutils.CalBigNotionalInt64(info.Price, info.CumQty))
+	case order.Side.SELL:
+		exported.Side = "SELL"
+		exported.Locked = sdk.NewCoin(baseAsset, info.Quantity-info.CumQty)
+	default:
+		return nil, fmt.Errorf("order %s has unknown side %d", info.Id, info.Side)
+	}
+	return exported, nil
+}
+
+// ExportOpenOrders exports the open orders of every trading pair, and
+// reconciles the coins they lock with the locked coins of the accounts.
+func ExportOpenOrders(app *app.BNBBeaconChain) (*types.ExportedOrders, error) {
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
+
+	exported := &types.ExportedOrders{
+		ChainID:     app.CheckState.Ctx.ChainID(),
+		BlockHeight: app.LastBlockHeight(),
+		Orders:      make([]*types.ExportedOrder, 0),
+		Mismatches:  make([]*types.LockedMismatch, 0),
+	}
+	ordersLocked := make(map[string]sdk.Coins)
+	for _, orders := range app.DexKeeper.GetAllOrders() {
+		for _, info := range orders {
+			exportedOrder, err := exportOrder(info)
+			if err != nil {
+				return nil, err
+			}
+			exported.Orders = append(exported.Orders, exportedOrder)
+			owner := exportedOrder.Owner.String()
+			ordersLocked[owner] = ordersLocked[owner].Plus(sdk.Coins{exportedOrder.Locked})
+		}
+	}
+	sort.Slice(exported.Orders, func(i, j int) bool {
+		if exported.Orders[i].Pair != exported.Orders[j].Pair {
+			return exported.Orders[i].Pair < exported.Orders[j].Pair
+		}
+		return exported.Orders[i].ID < exported.Orders[j].ID
+	})
+	trace("open orders", len(exported.Orders), "owners", len(ordersLocked))
+
+	// accounts with locked coins and no open order are mismatches too
+	app.AccountKeeper.IterateAccounts(ctx, func(acc sdk.Account) (stop bool) {
+		accountLocked := acc.(nodetypes.NamedAccount).GetLockedCoins()
+		owner := acc.GetAddress().String()
+		orderLocked, exist := ordersLocked[owner]
+		delete(ordersLocked, owner)
+		if !exist && accountLocked.IsZero() {
+			return false
+		}
+		if !orderLocked.IsEqual(accountLocked) {
+			exported.Mismatches = append(exported.Mismatches, &types.LockedMismatch{
+				Owner:         acc.GetAddress(),
+				OrdersLocked:  orderLocked,
+				AccountLocked: accountLocked,
+			})
+		}
+		return false
+	})
+	// owners of open orders without an account
+	for owner, orderLocked := range ordersLocked {
+		addr, err := sdk.AccAddressFromBech32(owner)
+		if err != nil {
+			return nil, err
+		}
+		exported.Mismatches = append(exported.Mismatches, &types.LockedMismatch{
+			Owner:         addr,
+			OrdersLocked:  orderLocked,
+			AccountLocked: sdk.Coins{},
+		})
+	}
+	sort.Slice(exported.Mismatches, func(i, j int) bool {
+		return exported.Mismatches[i].Owner.String() < exported.Mismatches[j].Owner.String()
+	})
+	return exported, nil
+}
+
+// ExportOrdersCmd exports the open orders of the DEX.
+func ExportOrdersCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-orders <path>",
+		Short: "Export the open DEX orders and reconcile them with the locked coins",
+		Long: `Export the open DEX orders of every trading pair at the last block height.
+
+The orders are loaded by the order keeper from the snapshot of the last breathe
+block and the blocks replayed after it. Each order is exported with the coin it
+still locks, and the coins locked by the orders of each owner are reconciled with
+the locked coins of the account. The orders are written to <path>/orders.json,
+the command fails when an owner does not reconcile. The application DB is
+opened read only.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<path> should be set")
+			}
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			exported, err := ExportOpenOrders(dapp)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(args[0], 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(path.Join(args[0], "orders.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			err = writeJSONFile(file, exported)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Open orders exported:", len(exported.Orders), "height:", exported.BlockHeight)
+
+			if len(exported.Mismatches) > 0 {
+				for _, mismatch := range exported.Mismatches {
+					trace("locked mismatch", mismatch.Owner.String(),
+						"orders", mismatch.OrdersLocked.String(), "account", mismatch.AccountLocked.String())
+				}
+				return fmt.Errorf("locked coins of %d accounts do not match their open orders", len(exported.Mismatches))
+			}
+			fmt.Println("Locked coins reconciled")
+
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	flagForce = "force"
+)
+
+// exportOutput stages the exported files in a directory next to the output
+// directory, and moves them into the output directory only once the export
+// succeeded, so a failed export never leaves files that look finished. The
+// files of an existing export are moved aside before the staged ones take
+// their place, the other files of the output directory are kept.
+type exportOutput struct {
+	dir     string
+	staging string
+	old     string
+}
+
+// renameFile is os.Rename, replaced by the tests to fail a commit.
+var renameFile = os.Rename
+
+// newExportOutput prepares the staging directory of an export into dir. It
+// refuses to overwrite an existing export unless force is set, and to write
+// into a non-empty directory without an export. When resume is set, the
+// staging directory of the interrupted export is kept.
+func newExportOutput(dir string, force bool, resume bool) (*exportOutput, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	staging := filepath.Join(filepath.Dir(dir), "."+filepath.Base(dir)+".partial")
+	old := filepath.Join(filepath.Dir(dir), "."+filepath.Base(dir)+".old")
+	if err := recoverOutput(dir, staging, old); err != nil {
+		return nil, err
+	}
+
+	exist, err := hasExport(dir)
+	if err != nil {
+		return nil, err
+	}
+	if exist && !force {
+		return nil, fmt.Errorf("%s already contains an export, use --%s to overwrite it", dir, flagForce)
+	}
+	if !exist {
+		empty, err := isEmptyDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !empty {
+			return nil, fmt.Errorf("%s is not empty and contains no export", dir)
+		}
+	}
+
+	if !resume {
+		if err := os.RemoveAll(staging); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return nil, err
+	}
+	return &exportOutput{
+		dir:     dir,
+		staging: staging,
+		old:     old,
+	}, nil
+}
+
+// outputFiles are the files of an export in the output directory.
+func outputFiles() []string {
+	return append(append([]string{}, exportedFiles...), manifestFile)
+}
+
+// moveFiles moves the files of an export found in from into to.
+func moveFiles(from string, to string) error {
+	for _, name := range outputFiles() {
+		if _, err := os.Lstat(filepath.Join(from, name)); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := renameFile(filepath.Join(from, name), filepath.Join(to, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recoverOutput completes a commit interrupted between its renames. The files
+// of the previous export are put back while the staged ones are not all in
+// place, and removed otherwise. The staged files already moved into dir go
+// back to the staging directory.
+func recoverOutput(dir string, staging string, old string) error {
+	aside := old + ".partial"
+	if _, err := os.Stat(aside); err == nil {
+		trace("restore the previous export of an interrupted commit", dir)
+		if err := moveFiles(aside, dir); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(aside); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if _, err := os.Stat(old); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	staged, err := hasExport(staging)
+	if err != nil {
+		return err
+	}
+	if staged {
+		trace("restore the previous export of an interrupted commit", dir)
+		return restoreOutput(dir, staging, old)
+	}
+	return os.RemoveAll(old)
+}
+
+// restoreOutput puts the files of the previous export moved to old back into
+// dir, and the staged files already moved into dir back into staging.
+func restoreOutput(dir string, staging string, old string) error {
+	for _, name := range outputFiles() {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		inDir := err == nil
+		if _, err := os.Lstat(filepath.Join(staging, name)); os.IsNotExist(err) && inDir {
+			// all the files of the previous export are in old, so this one is staged
+			if err := renameFile(filepath.Join(dir, name), filepath.Join(staging, name)); err != nil {
+				return err
+			}
+		} else if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := moveFiles(old, dir); err != nil {
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// Commit moves the staged files into the output directory. The files of an
+// existing export are moved aside first and removed once the staged ones are
+// in place, so the output directory never holds a mix of two exports.
+func (o *exportOutput) Commit() error {
+	if err := os.RemoveAll(filepath.Join(o.staging, checkpointDirName)); err != nil {
+		return err
+	}
+	parent := filepath.Dir(o.dir)
+	if _, err := os.Stat(o.dir); os.IsNotExist(err) {
+		if err := renameFile(o.staging, o.dir); err != nil {
+			return err
+		}
+		return syncDir(parent)
+	} else if err != nil {
+		return err
+	}
+
+	// the previous export is complete in old only once all its files are moved
+	aside := o.old + ".partial"
+	for _, d := range []string{o.old, aside} {
+		if err := os.RemoveAll(d); err != nil {
+			return err
+		}
+	}
+	if err := os.Mkdir(aside, 0755); err != nil {
+		return err
+	}
+	err := moveFiles(o.dir, aside)
+	if err == nil {
+		err = syncDir(aside)
+	}
+	if err == nil {
+		err = renameFile(aside, o.old)
+	}
+	if err != nil {
+		if restoreErr := moveFiles(aside, o.dir); restoreErr != nil {
+			return fmt.Errorf("%w, and restoring the previous export %s failed: %v", err, aside, restoreErr)
+		}
+		os.Remove(aside)
+		return err
+	}
+	if err := syncDir(parent); err != nil {
+		return err
+	}
+
+	if err := moveFiles(o.staging, o.dir); err != nil {
+		if restoreErr := restoreOutput(o.dir, o.staging, o.old); restoreErr != nil {
+			return fmt.Errorf("%w, and restoring the previous export %s failed: %v", err, o.old, restoreErr)
+		}
+		return err
+	}
+	if err := syncDir(o.dir); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(o.staging); err != nil {
+		return err
+	}
+	return os.RemoveAll(o.old)
+}
+
+// Abort removes the staged files, it is a no-op after Commit. The checkpoint
+// is kept so that the export can be resumed.
+func (o *exportOutput) Abort() error {
+	entries, err := os.ReadDir(o.staging)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == checkpointDirName {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(o.staging, entry.Name())); err != nil {
+			return err
+		}
+	}
+	// only removed when there is no checkpoint left
+	os.Remove(o.staging)
+	return nil
+}
+
+// hasExport reports whether dir contains any exported file.
+func hasExport(dir string) (bool, error) {
+	for _, name := range outputFiles() {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if err == nil {
+			return true, nil
+		}
+		if !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// isEmptyDir reports whether dir is missing or has no entry.
+func isEmptyDir(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(entries) == 0, nil
+}
+
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// stageExport writes the exported files with content into the staging
+// directory of an export into dir.
+func stageExport(t *testing.T, dir string, force bool, content string) *exportOutput {
+	output, err := newExportOutput(dir, force, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range exportedFiles {
+		if err := os.WriteFile(filepath.Join(output.staging, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(output.staging, checkpointDirName), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+func checkExport(t *testing.T, dir string, content string) {
+	checkFiles(t, dir, content)
+	if _, err := os.Stat(filepath.Join(dir, checkpointDirName)); !os.IsNotExist(err) {
+		t.Error("the checkpoint should not be committed")
+	}
+}
+
+// checkFiles checks that the exported files in dir hold content.
+func checkFiles(t *testing.T, dir string, content string) {
+	for _, name := range exportedFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s is %q, expected %q", name, data, content)
+		}
+	}
+}
+
+// checkNoLeftover checks that only dir is left in its parent.
+func checkNoLeftover(t *testing.T, dir string) {
+	entries, err := os.ReadDir(filepath.Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(dir) {
+		t.Errorf("unexpected entries next to the export: %v", entries)
+	}
+}
+
+func TestExportOutputNewDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "output")
+	output := stageExport(t, dir, false, "new")
+	if err := output.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	checkExport(t, dir, "new")
+	checkNoLeftover(t, dir)
+}
+
+func TestExportOutputExistingDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "output")
+	if err := stageExport(t, dir, false, "old").Commit(); err != nil {
+		t.Fatal(err)
+	}
+	// written by the export to sign, and by the other commands into the export
+	for _, name := range []string{manifestFile, lightBundleFile, "stats.json", "blocks.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("old"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := newExportOutput(dir, false, false); err == nil {
+		t.Fatal("an existing export should not be overwritten without force")
+	}
+	output := stageExport(t, dir, true, "new")
+	if err := output.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	checkExport(t, dir, "new")
+	if _, err := os.Stat(filepath.Join(dir, manifestFile)); !os.IsNotExist(err) {
+		t.Error("the manifest of the previous export should be removed")
+	}
+	for _, name := range []string{lightBundleFile, "stats.json", "blocks.json"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != "old" {
+			t.Errorf("%s should be kept, read %q, %v", name, data, err)
+		}
+	}
+	checkNoLeftover(t, dir)
+}
+
+func TestExportOutputNonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(kept, []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, force := range []bool{false, true} {
+		if _, err := newExportOutput(dir, force, false); err == nil {
+			t.Fatalf("a directory without an export should be refused, force %v", force)
+		}
+	}
+	if data, err := os.ReadFile(kept); err != nil || string(data) != "kept" {
+		t.Errorf("notes.txt should be kept, read %q, %v", data, err)
+	}
+}
+
+// failRename makes the nth rename from now on fail.
+func failRename(t *testing.T, n int) {
+	calls := 0
+	renameFile = func(from, to string) error {
+		calls++
+		if calls == n {
+			return fmt.Errorf("rename %s failed", from)
+		}
+		return os.Rename(from, to)
+	}
+	t.Cleanup(func() { renameFile = os.Rename })
+}
+
+func TestExportOutputFailedCommit(t *testing.T) {
+	// the renames of a commit: the files of the previous export to the side
+	// directory, the side directory to old, then the staged files into place
+	renames := 2*len(exportedFiles) + 1
+	for n := 1; n <= renames; n++ {
+		dir := filepath.Join(t.TempDir(), "output")
+		if err := stageExport(t, dir, false, "old").Commit(); err != nil {
+			t.Fatal(err)
+		}
+		kept := filepath.Join(dir, lightBundleFile)
+		if err := os.WriteFile(kept, []byte("kept"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		output := stageExport(t, dir, true, "new")
+		failRename(t, n)
+		if err := output.Commit(); err == nil {
+			t.Fatalf("expected the commit to fail at rename %d", n)
+		}
+		renameFile = os.Rename
+		checkExport(t, dir, "old")
+		checkFiles(t, output.staging, "new")
+		if _, err := os.Stat(kept); err != nil {
+			t.Error(err)
+		}
+
+		// the staged export can still be committed
+		if err := output.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		checkExport(t, dir, "new")
+		checkNoLeftover(t, dir)
+	}
+}
+
+func TestExportOutputInterruptedCommit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "output")
+	if err := stageExport(t, dir, false, "old").Commit(); err != nil {
+		t.Fatal(err)
+	}
+	output := stageExport(t, dir, true, "new")
+	aside := output.old + ".partial"
+
+	// interrupted while the previous export was moved aside
+	if err := os.Mkdir(aside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, exportedFiles[0]), filepath.Join(aside, exportedFiles[0])); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newExportOutput(dir, false, true); err == nil {
+		t.Fatal("the previous export should be restored")
+	}
+	checkExport(t, dir, "old")
+
+	// interrupted while the staged files were moved into place
+	if err := os.Mkdir(output.old, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range exportedFiles {
+		if err := os.Rename(filepath.Join(dir, name), filepath.Join(output.old, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Rename(filepath.Join(output.staging, exportedFiles[0]), filepath.Join(dir, exportedFiles[0])); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newExportOutput(dir, false, true); err == nil {
+		t.Fatal("the previous export should be restored")
+	}
+	checkExport(t, dir, "old")
+	checkFiles(t, output.staging, "new")
+
+	// interrupted after the staged files were moved into place
+	if err := os.Mkdir(output.old, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveFiles(output.staging, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newExportOutput(dir, true, false); err != nil {
+		t.Fatal(err)
+	}
+	checkExport(t, dir, "new")
+	if _, err := os.Stat(output.old); !os.IsNotExist(err) {
+		t.Error("the previous export should be removed")
+	}
+}
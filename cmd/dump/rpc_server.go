@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	amino "github.com/tendermint/go-amino"
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/rpc/core"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpcserver "github.com/tendermint/tendermint/rpc/lib/server"
+	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
+	tmversion "github.com/tendermint/tendermint/version"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+
+	"github.com/bnb-chain/node/app"
+
+	"github.com/bnb-chain/node-dump/version"
+)
+
+// rpcStatus answers the status of the archive, the last block of the block
+// store is its latest block and it is never catching up.
+type rpcStatus struct {
+	chainID string
+	reader  *blockReader
+}
+
+func (s *rpcStatus) Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
+	result := &ctypes.ResultStatus{
+		NodeInfo: p2p.DefaultNodeInfo{
+			Network: s.chainID,
+			Version: tmversion.TMCoreSemVer,
+			Moniker: "node-dump " + version.Version,
+		},
+	}
+	if height := s.reader.blockStore.Height(); height > 0 {
+		meta := s.reader.blockStore.LoadBlockMeta(height)
+		if meta == nil {
+			return nil, fmt.Errorf("block %d is not in the block store", height)
+		}
+		result.SyncInfo = ctypes.SyncInfo{
+			LatestBlockHash:   meta.BlockID.Hash,
+			LatestAppHash:     meta.Header.AppHash,
+			LatestBlockHeight: height,
+			LatestBlockTime:   meta.Header.Time,
+		}
+	} else {
+		result.SyncInfo.LatestBlockTime = time.Unix(0, 0)
+	}
+	return result, nil
+}
+
+// rpcMux serves the methods of the Tendermint RPC answered by the app and the
+// block store of the archive. The handlers of Tendermint read them from the
+// globals of its core package.
+func rpcMux(dapp *app.BNBBeaconChain, reader *blockReader, logger log.Logger) *http.ServeMux {
+	core.SetLogger(logger)
+	core.SetBlockStore(reader.blockStore)
+	core.SetStateDB(reader.stateDB)
+	core.SetProxyAppQuery(proxy.NewAppConnQuery(abcicli.NewLocalClient(new(sync.Mutex), dapp)))
+	status := &rpcStatus{chainID: dapp.CheckState.Ctx.ChainID(), reader: reader}
+	routes := map[string]*rpcserver.RPCFunc{
+		"abci_query": rpcserver.NewRPCFunc(core.ABCIQuery, "path,data,height,prove"),
+		"status":     rpcserver.NewRPCFunc(status.Status, ""),
+		"block":      rpcserver.NewRPCFunc(core.Block, "height"),
+		"commit":     rpcserver.NewRPCFunc(core.Commit, "height"),
+	}
+
+	rpcCodec := amino.NewCodec()
+	ctypes.RegisterAmino(rpcCodec)
+	mux := http.NewServeMux()
+	rpcserver.RegisterRPCFuncs(mux, routes, rpcCodec, logger)
+	return mux
+}
+
+// RPCServerCmd serves a subset of the Tendermint RPC from the archive.
+func RPCServerCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rpc-server",
+		Short: "Serve abci_query, status, block and commit of the Tendermint RPC from the archive",
+		Long: `Serve abci_query, status, block and commit of the Tendermint RPC from the
+application DB, the block store and the state DB of --home, without starting
+the consensus. The DBs are opened read only.
+
+abci_query is answered by the app, with the IAVL proofs of the store queries
+when prove is set. status reports the last block of the block store, block and
+commit are read from the block store. The methods are served over JSON-RPC and
+URI like a node, so the RPC clients of Tendermint can query the archive.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			// the app opens the block store and the state DB while it starts,
+			// they are opened read only once it has closed them
+			reader, err := openBlockReader(viper.GetString("home"))
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+
+			config := rpcserver.DefaultConfig()
+			listener, err := rpcserver.Listen(viper.GetString(flagListenAddr), config)
+			if err != nil {
+				return err
+			}
+			fmt.Println("RPC server listening on", listener.Addr(), "height:", reader.blockStore.Height())
+			return rpcserver.StartHTTPServer(listener, rpcMux(dapp, reader, ctx.Logger), ctx.Logger, config)
+		},
+	}
+	cmd.Flags().String(flagListenAddr, "tcp://localhost:27147", "address to listen on")
+	return cmd
+}
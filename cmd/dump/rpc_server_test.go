@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+func TestRPCServer(t *testing.T) {
+	home := t.TempDir()
+	addr := testAddress(1)
+	newTestArchive(t, home, addr)
+	dapp, db, err := openReadOnlyApp(server.NewDefaultContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	reader, err := openBlockReader(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	ts := httptest.NewServer(rpcMux(dapp, reader, log.NewNopLogger()))
+	defer ts.Close()
+	client := rpcclient.NewHTTP(ts.URL, "/websocket")
+	block := reader.blockStore.LoadBlock(1)
+
+	query, err := client.ABCIQueryWithOptions("/store/acc/key", auth.AddressStoreKey(addr), rpcclient.ABCIQueryOptions{Prove: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !query.Response.IsOK() || len(query.Response.Value) == 0 {
+		t.Fatalf("unexpected query response %+v", query.Response)
+	}
+	if query.Response.Proof == nil || len(query.Response.Proof.Ops) == 0 {
+		t.Error("no proof of the account with prove set")
+	}
+	if query.Response.Height != 1 {
+		t.Errorf("query at height %d, want 1", query.Response.Height)
+	}
+
+	status, err := client.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.NodeInfo.Network != dapp.CheckState.Ctx.ChainID() {
+		t.Errorf("network %q, want %q", status.NodeInfo.Network, dapp.CheckState.Ctx.ChainID())
+	}
+	if status.SyncInfo.LatestBlockHeight != 1 || !bytes.Equal(status.SyncInfo.LatestBlockHash, block.Hash()) {
+		t.Errorf("latest block %d %X, want 1 %X", status.SyncInfo.LatestBlockHeight, status.SyncInfo.LatestBlockHash, block.Hash())
+	}
+	if status.SyncInfo.CatchingUp {
+		t.Error("archive is catching up")
+	}
+
+	height := int64(1)
+	result, err := client.Block(&height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Block.Height != 1 || !bytes.Equal(result.BlockMeta.BlockID.Hash, block.Hash()) {
+		t.Errorf("block %d %X, want 1 %X", result.Block.Height, result.BlockMeta.BlockID.Hash, block.Hash())
+	}
+
+	commit, err := client.Commit(&height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Header == nil || commit.Header.Height != 1 || !bytes.Equal(commit.Header.Hash(), block.Hash()) {
+		t.Errorf("unexpected header of the commit %+v", commit.Header)
+	}
+
+	// a block after the last one of the archive is not answered
+	height = 2
+	if _, err := client.Block(&height); err == nil {
+		t.Error("block 2 of an archive of height 1 answered")
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/sidechain"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	stakekeeper "github.com/cosmos/cosmos-sdk/x/stake/keeper"
+	staketypes "github.com/cosmos/cosmos-sdk/x/stake/types"
+
+	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common"
+	nodetypes "github.com/bnb-chain/node/common/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// newStakeKeepers returns read only stake and side chain keepers over the
+// stores of the app, the keepers of the app are not exported.
+func newStakeKeepers(dapp *app.BNBBeaconChain) (stake.Keeper, *sidechain.Keeper, error) {
+	stakeSpace, ok := dapp.ParamHub.GetSubspace(stake.DefaultParamspace)
+	if !ok {
+		return stake.Keeper{}, nil, fmt.Errorf("param subspace %s is not registered", stake.DefaultParamspace)
+	}
+	sideChainSpace, ok := dapp.ParamHub.GetSubspace(sidechain.DefaultParamspace)
+	if !ok {
+		return stake.Keeper{}, nil, fmt.Errorf("param subspace %s is not registered", sidechain.DefaultParamspace)
+	}
+	stakeKeeper := stake.NewKeeper(dapp.Codec,
+		common.StakeStoreKey, common.StakeRewardStoreKey, common.TStakeStoreKey,
+		dapp.CoinKeeper, nil, stakeSpace, stake.DefaultCodespace,
+		sdk.ChainID(app.ServerContext.BscIbcChainId), app.ServerContext.BscChainId)
+	sideChainKeeper := sidechain.NewKeeper(common.SideChainStoreKey, sideChainSpace, dapp.Codec)
+	return stakeKeeper, &sideChainKeeper, nil
+}
+
+// exportStakingChain exports the staking state in the store of ctx, ctx has the
+// key prefix of the side chain when sideChainID is set.
+func exportStakingChain(app *app.BNBBeaconChain, keeper stake.Keeper, ctx sdk.Context, sideChainID string) *types.ExportedStakingChain {
+	chain := &types.ExportedStakingChain{
+		SideChainID:          sideChainID,
+		Params:               keeper.GetParams(ctx),
+		Pool:                 keeper.GetPool(ctx),
+		Validators:           keeper.GetAllValidators(ctx),
+		Delegations:          keeper.GetAllDelegations(ctx),
+		UnbondingDelegations: make([]stake.UnbondingDelegation, 0),
+		Redelegations:        make([]stake.Redelegation, 0),
+		PendingRewards:       make([]staketypes.Reward, 0),
+		DistributionBalances: make([]*types.DistributionBalance, 0),
+	}
+	if chain.Validators == nil {
+		chain.Validators = make([]stake.Validator, 0)
+	}
+	if chain.Delegations == nil {
+		chain.Delegations = make([]stake.Delegation, 0)
+	}
+	keeper.IterateUnbondingDelegations(ctx, func(_ int64, ubd stake.UnbondingDelegation) (stop bool) {
+		chain.UnbondingDelegations = append(chain.UnbondingDelegations, ubd)
+		return false
+	})
+
+	// the keeper has no iterator over all the redelegations and rewards
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(common.StakeStoreKey), stakekeeper.RedelegationKey)
+	for ; iter.Valid(); iter.Next() {
+		chain.Redelegations = append(chain.Redelegations, staketypes.MustUnmarshalRED(app.Codec, iter.Key(), iter.Value()))
+	}
+	iter.Close()
+	iter = sdk.KVStorePrefixIterator(ctx.KVStore(common.StakeRewardStoreKey), stakekeeper.RewardBatchKey)
+	for ; iter.Valid(); iter.Next() {
+		chain.PendingRewards = append(chain.PendingRewards, staketypes.MustUnmarshalRewards(app.Codec, iter.Value())...)
+	}
+	iter.Close()
+
+	totals := &chain.Totals
+	totals.DistributionBalance = sdk.Coins{}
+	for _, validator := range chain.Validators {
+		totals.Tokens += validator.Tokens.RawInt()
+		if len(validator.DistributionAddr) == 0 {
+			continue
+		}
+		balance := &types.DistributionBalance{
+			Validator: validator.OperatorAddr,
+			Address:   validator.DistributionAddr,
+			Coins:     sdk.Coins{},
+		}
+		if acc := app.AccountKeeper.GetAccount(ctx, validator.DistributionAddr); acc != nil {
+			_, _, _, balance.Coins = accountBalances(acc.(nodetypes.NamedAccount))
+		}
+		chain.DistributionBalances = append(chain.DistributionBalances, balance)
+		totals.DistributionBalance = totals.DistributionBalance.Plus(balance.Coins)
+	}
+	for _, ubd := range chain.UnbondingDelegations {
+		totals.Unbonding += ubd.Balance.Amount
+	}
+	for _, red := range chain.Redelegations {
+		totals.Redelegating += red.Balance.Amount
+	}
+	for _, reward := range chain.PendingRewards {
+		totals.PendingRewards += reward.Amount
+	}
+	totals.Validators = int64(len(chain.Validators))
+	totals.Delegations = int64(len(chain.Delegations))
+	totals.UnbondingDelegations = int64(len(chain.UnbondingDelegations))
+	totals.Redelegations = int64(len(chain.Redelegations))
+	return chain
+}
+
+// ExportStaking exports the staking state of the Beacon Chain and of the side
+// chains, and reconciles it with the delegation escrow.
+func ExportStaking(app *app.BNBBeaconChain) (*types.ExportedStaking, error) {
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	stakeKeeper, sideChainKeeper, err := newStakeKeepers(app)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := &types.ExportedStaking{
+		ChainID:     app.CheckState.Ctx.ChainID(),
+		BlockHeight: app.LastBlockHeight(),
+		BondDenom:   stakeKeeper.BondDenom(ctx),
+	}
+	exported.Chains = append(exported.Chains, exportStakingChain(app, stakeKeeper, ctx, ""))
+	sideChainIDs, prefixes := sideChainKeeper.GetAllSideChainPrefixes(ctx)
+	for i, sideChainID := range sideChainIDs {
+		sideCtx := ctx.WithSideChainKeyPrefix(prefixes[i])
+		exported.Chains = append(exported.Chains, exportStakingChain(app, stakeKeeper, sideCtx, sideChainID))
+	}
+
+	// the bonded and unbonding tokens of every chain stay in the escrow
+	escrow := &exported.Escrow
+	escrow.Address = stake.DelegationAccAddr
+	if acc := app.AccountKeeper.GetAccount(ctx, stake.DelegationAccAddr); acc != nil {
+		_, _, _, coins := accountBalances(acc.(nodetypes.NamedAccount))
+		escrow.Balance = coins.AmountOf(exported.BondDenom)
+	}
+	for _, chain := range exported.Chains {
+		escrow.Tokens += chain.Totals.Tokens
+		escrow.Unbonding += chain.Totals.Unbonding
+		trace("staking", "side chain", chain.SideChainID, "validators", chain.Totals.Validators,
+			"delegations", chain.Totals.Delegations, "tokens", chain.Totals.Tokens)
+	}
+	escrow.Difference = escrow.Balance - escrow.Tokens - escrow.Unbonding
+	return exported, nil
+}
+
+// ExportStakingCmd exports the staking state.
+func ExportStakingCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-staking <path>",
+		Short: "Export the validators, delegations and side chain staking state",
+		Long: `Export the staking state at the last block height.
+
+The validators, delegations, unbonding delegations, redelegations and pending
+rewards are exported for the Beacon Chain and each side chain, with the balances
+of the distribution addresses of the validators and the totals of each
+chain. The tokens of the validators and of the unbonding delegations are
+reconciled with the balance of the delegation escrow. The state is written to
+<path>/staking.json. The application DB is opened read only.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<path> should be set")
+			}
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			exported, err := ExportStaking(dapp)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(args[0], 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(path.Join(args[0], "staking.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			err = writeJSONFile(file, exported)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Staking state exported, chains:", len(exported.Chains), "height:", exported.BlockHeight)
+
+			if exported.Escrow.Difference != 0 {
+				return fmt.Errorf("delegation escrow balance %d does not match the tokens %d and the unbonding %d",
+					exported.Escrow.Balance, exported.Escrow.Tokens, exported.Escrow.Unbonding)
+			}
+			fmt.Println("Delegation escrow reconciled")
+
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bnb-chain/node-dump/stats"
+	"github.com/bnb-chain/node-dump/types"
+	"github.com/bnb-chain/node-dump/util"
+)
+
+const flagTop = "top"
+
+// CollectStats streams the exported accounts of dir and collects the
+// distribution of their denoms with the topN holders of each denom.
+func CollectStats(dir string, topN int) (*types.HolderStats, error) {
+	stream := util.NewJSONStream(func() any {
+		return &types.ExportedAccount{}
+	})
+	go stream.Start(path.Join(dir, "accounts.json"))
+
+	collector := stats.NewCollector(topN)
+	for data := range stream.Watch() {
+		if data.Error != nil {
+			return nil, data.Error
+		}
+		account := data.Data.(*types.ExportedAccount)
+		collector.Add(account.Address, account.Coins)
+	}
+	return collector.Stats(), nil
+}
+
+// StatsCmd reports the distribution of the denoms among the exported accounts.
+func StatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats <dir>",
+		Short: "Report the distribution of each denom among the exported accounts",
+		Long: `Report the distribution of each denom among the accounts exported to <dir>.
+
+The accounts of <dir>/accounts.json are streamed, the total balance of each
+account counts, the available, frozen and locked coins together. Each denom is
+reported with its number of holders, total, --top largest holders, the
+percentiles of the balances of its holders, the shares of the total held by
+the top 1% and 10% of its holders and the Gini coefficient of the balances.
+The accounts without a nonzero coin are counted apart. The stats are written
+to <dir>/stats.json and printed as a table.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || args[0] == "" {
+				return fmt.Errorf("<dir> should be set")
+			}
+			exported, err := CollectStats(args[0], viper.GetInt(flagTop))
+			if err != nil {
+				return err
+			}
+
+			file, err := os.OpenFile(path.Join(args[0], "stats.json"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			if err := writeJSONFile(file, exported); err != nil {
+				return err
+			}
+
+			writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprint(writer, "DENOM\tHOLDERS\tTOTAL")
+			for _, p := range stats.Percentiles {
+				fmt.Fprintf(writer, "\tP%g", p)
+			}
+			fmt.Fprintln(writer, "\tTOP 1%\tTOP 10%\tGINI\tLARGEST HOLDER")
+			for _, denom := range exported.Denoms {
+				fmt.Fprintf(writer, "%s\t%d\t%d", denom.Denom, denom.Holders, denom.Total)
+				for _, p := range denom.Percentiles {
+					fmt.Fprintf(writer, "\t%d", p.Amount)
+				}
+				largest := ""
+				if len(denom.Top) > 0 {
+					largest = denom.Top[0].Address.String()
+				}
+				fmt.Fprintf(writer, "\t%.2f%%\t%.2f%%\t%.4f\t%s\n",
+					denom.Top1Share*100, denom.Top10Share*100, denom.Gini, largest)
+			}
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			fmt.Println("Accounts:", exported.Accounts, "without a nonzero coin:", exported.EmptyAccounts,
+				"denoms:", len(exported.Denoms))
+
+			return nil
+		},
+	}
+	cmd.Flags().Int(flagTop, 10, "number of the largest holders of each denom")
+	return cmd
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// commitInfoKey is the key of the commit info of version in the application
+// DB, written by the multistore on commit.
+func commitInfoKey(version int64) []byte {
+	return []byte(fmt.Sprintf("s/%d", version))
+}
+
+// ExportStores lists the IAVL stores mounted in the multistore of the app, and
+// recomputes the commit hash from their root hashes.
+func ExportStores(app *app.BNBBeaconChain, db dbm.DB) (*types.ExportedStores, error) {
+	commitID := app.LastCommitID()
+	data := db.Get(commitInfoKey(commitID.Version))
+	if data == nil {
+		return nil, fmt.Errorf("no commit info of version %d", commitID.Version)
+	}
+	var info store.CommitInfo
+	if err := app.Codec.UnmarshalBinaryLengthPrefixed(data, &info); err != nil {
+		return nil, fmt.Errorf("decode commit info of version %d: %w", commitID.Version, err)
+	}
+	// the leaf of a store is its root hash since BEP171, the hash of its commit
+	// ID before
+	leaves := make(map[string][]byte, len(info.StoreInfos))
+	for _, storeInfo := range info.StoreInfos {
+		if sdk.IsUpgradeWithHeight(sdk.BEP171, info.Version) {
+			leaves[storeInfo.Name] = storeInfo.GetHash()
+		} else {
+			leaves[storeInfo.Name] = storeInfo.Hash()
+		}
+	}
+
+	exported := &types.ExportedStores{
+		ChainID:     app.CheckState.Ctx.ChainID(),
+		BlockHeight: app.LastBlockHeight(),
+		CommitID:    commitID,
+		Stores:      make([]*types.StoreInventory, 0, len(common.StoreKeyNameMap)),
+	}
+	computed := store.CommitInfo{Version: info.Version}
+	for name, key := range common.StoreKeyNameMap {
+		iavlStore, ok := app.GetCommitMultiStore().GetCommitStore(key).(*store.IavlStore)
+		if !ok {
+			continue
+		}
+		last := iavlStore.LastCommitID()
+		inventory := &types.StoreInventory{
+			Name:     name,
+			Version:  last.Version,
+			RootHash: last.Hash,
+		}
+		if leaf, ok := leaves[name]; ok {
+			inventory.LeafValue = leaf
+			inventory.Committed = true
+			computed.StoreInfos = append(computed.StoreInfos, store.StoreInfo{Name: name, Core: store.StoreCore{CommitID: last}})
+		}
+		tree := iavlStore.GetImmutableTree()
+		inventory.Keys = tree.Size()
+		tree.Iterate(func(key []byte, value []byte) bool {
+			inventory.Bytes += int64(len(key) + len(value))
+			return false
+		})
+		trace("store", name, "keys", inventory.Keys, "bytes", inventory.Bytes)
+		exported.Stores = append(exported.Stores, inventory)
+	}
+	sort.Slice(exported.Stores, func(i, j int) bool {
+		return exported.Stores[i].Name < exported.Stores[j].Name
+	})
+	exported.ComputedHash = computed.Hash()
+	return exported, nil
+}
+
+// StoresCmd lists the stores of the multistore.
+func StoresCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stores",
+		Short: "List the KV stores of the multistore and how they make the commit hash",
+		Long: `List the IAVL stores mounted in the multistore at the last block height.
+
+Each store is listed with its IAVL version, root hash, number of keys and size
+of its keys and values. The commit hash is the simple merkle root of the leaf
+values of the committed stores keyed by their names, the leaf value of a store
+is its root hash since BEP171 and the hash of its commit ID before. The commit
+hash is recomputed from the stores and compared with the last commit ID of the
+app. The inventory is written to --out in JSON. The application DB is opened
+read only.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dapp, db, err := openReadOnlyApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			exported, err := ExportStores(dapp, db)
+			if err != nil {
+				return err
+			}
+
+			writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "STORE\tVERSION\tKEYS\tBYTES\tROOT HASH\tLEAF VALUE")
+			for _, inventory := range exported.Stores {
+				leaf := "not committed"
+				if inventory.Committed {
+					leaf = inventory.LeafValue.String()
+				}
+				fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t%s\t%s\n", inventory.Name, inventory.Version,
+					inventory.Keys, inventory.Bytes, inventory.RootHash, leaf)
+			}
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			fmt.Printf("Commit hash of height %d: %X, computed: %s\n",
+				exported.CommitID.Version, exported.CommitID.Hash, exported.ComputedHash)
+
+			if out := viper.GetString(flagOut); out != "" {
+				file, err := os.OpenFile(out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				if err := writeJSONFile(file, exported); err != nil {
+					return err
+				}
+			}
+
+			if !bytes.Equal(exported.CommitID.Hash, exported.ComputedHash) {
+				return fmt.Errorf("computed hash %s does not match the commit hash %X", exported.ComputedHash, exported.CommitID.Hash)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(flagOut, "", "output file of the inventory in JSON")
+	return cmd
+}
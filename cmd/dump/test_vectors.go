@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bnb-chain/node-dump/proof"
+)
+
+// TestVectorsCmd writes the test vectors of the leaf and proof encoding.
+func TestVectorsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test-vectors [path/vectors.json]",
+		Short: "Write the test vectors of the leaf and proof encoding",
+		Long: `Write the test vectors of the leaf and proof encoding, to stdout when no
+path is given.
+
+Each vector is a leaf with its packed encoding, keccak256 hash and proof against
+the root of its set. The vectors cover max length symbols, zero padded addresses
+and large amounts, for checking other implementations such as the recovery
+contract.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("at most one <path/vectors.json> should be set")
+			}
+			sets, err := proof.GenerateTestVectors()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 || args[0] == "" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "\t")
+				return encoder.Encode(sets)
+			}
+			file, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			err = writeJSONFile(file, sets)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Test vectors written:", args[0])
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/state/txindex/kv"
+	tmstore "github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/bnb-chain/node/app"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const (
+	flagAddressFile = "address-file"
+	flagTags        = "tags"
+)
+
+// txHistoryReader finds the transactions of an address in the tx index by the
+// tags whose value is the address.
+type txHistoryReader struct {
+	indexDB    dbm.DB
+	blockDB    dbm.DB
+	txIndex    *kv.TxIndex
+	blockStore *tmstore.BlockStore
+	tags       []string
+}
+
+func openTxHistoryReader(home string, tags []string) (*txHistoryReader, error) {
+	indexDB, err := openReadOnlyDB(home, "tx_index")
+	if err != nil {
+		return nil, fmt.Errorf("open tx index: %w", err)
+	}
+	blockDB, err := openReadOnlyDB(home, "blockstore")
+	if err != nil {
+		indexDB.Close()
+		return nil, fmt.Errorf("open block store: %w", err)
+	}
+	return &txHistoryReader{
+		indexDB:    indexDB,
+		blockDB:    blockDB,
+		txIndex:    kv.NewTxIndex(indexDB),
+		blockStore: tmstore.NewBlockStore(blockDB),
+		tags:       tags,
+	}, nil
+}
+
+func (r *txHistoryReader) Close() {
+	r.indexDB.Close()
+	r.blockDB.Close()
+}
+
+// History returns the transactions whose tags involve address, by height and
+// index.
+func (r *txHistoryReader) History(address string) (*types.TxHistory, error) {
+	// the keys of the tags are tag/value/height/index, the values tx hashes
+	hashes := make(map[string][]byte)
+	for _, tag := range r.tags {
+		iter := dbm.IteratePrefix(r.indexDB, []byte(tag+"/"+address+"/"))
+		for ; iter.Valid(); iter.Next() {
+			hashes[string(iter.Value())] = iter.Value()
+		}
+		iter.Close()
+	}
+
+	history := &types.TxHistory{
+		Address: address,
+		Txs:     make([]*types.ExportedTx, 0, len(hashes)),
+	}
+	for _, hash := range hashes {
+		result, err := r.txIndex.Get(hash)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return nil, fmt.Errorf("tx %X is tagged but not indexed", hash)
+		}
+		exported, err := r.exportTx(result)
+		if err != nil {
+			return nil, err
+		}
+		history.Txs = append(history.Txs, exported)
+	}
+	sort.Slice(history.Txs, func(i, j int) bool {
+		if history.Txs[i].Height != history.Txs[j].Height {
+			return history.Txs[i].Height < history.Txs[j].Height
+		}
+		return history.Txs[i].Index < history.Txs[j].Index
+	})
+	return history, nil
+}
+
+func (r *txHistoryReader) exportTx(result *tmtypes.TxResult) (*types.ExportedTx, error) {
+	exported := &types.ExportedTx{
+		Hash:     fmt.Sprintf("%X", result.Tx.Hash()),
+		Height:   result.Height,
+		Index:    result.Index,
+		Code:     result.Result.Code,
+		Messages: make([]*types.ExportedMsg, 0),
+	}
+	if exported.Code != 0 {
+		exported.Log = result.Result.Log
+	}
+	if meta := r.blockStore.LoadBlockMeta(result.Height); meta != nil {
+		exported.Time = meta.Header.Time
+	}
+
+	var tx auth.StdTx
+	if err := app.Codec.UnmarshalBinaryLengthPrefixed(result.Tx, &tx); err != nil {
+		return nil, fmt.Errorf("decode tx %s: %w", exported.Hash, err)
+	}
+	exported.Memo = tx.Memo
+	for _, msg := range tx.Msgs {
+		value, err := app.Codec.MarshalJSON(msg)
+		if err != nil {
+			return nil, err
+		}
+		exported.Messages = append(exported.Messages, &types.ExportedMsg{
+			Kind:  msgKind(msg.Route()),
+			Route: msg.Route(),
+			Type:  msg.Type(),
+			Value: value,
+		})
+	}
+	return exported, nil
+}
+
+// msgKind returns the kind of the messages of route.
+func msgKind(route string) string {
+	switch {
+	case route == "bank":
+		return "transfer"
+	case route == "orderNew" || route == "orderCancel":
+		return "order"
+	case strings.HasPrefix(route, "tokens") || strings.HasPrefix(route, "miniTokens") || route == "timelock":
+		return "token"
+	default:
+		return "other"
+	}
+}
+
+// loadAddressFile reads the addresses of a file, one per line, the empty lines
+// and the lines starting with # are skipped.
+func loadAddressFile(name string) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var addresses []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addresses = append(addresses, line)
+	}
+	return addresses, scanner.Err()
+}
+
+// TxHistoryCmd exports the transaction history of addresses from the tx index.
+func TxHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx-history",
+		Short: "Export the transaction history of addresses from the Tendermint tx index",
+		Long: `Export the transaction history of addresses from the Tendermint tx index.
+
+The tx index and the block store of --home are opened read only. The
+transactions are found by the tags whose value is the address, the tags given
+with --tags must have been indexed by the node. Each transaction is decoded and
+its messages are exported as transfers, orders, token operations or other
+messages. The history of --address is written to stdout, or to --out. With
+--address-file, the history of each address is written to <out>/<address>.json.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var addresses []string
+			if address := viper.GetString(flagAddress); address != "" {
+				addresses = append(addresses, address)
+			}
+			if addressFile := viper.GetString(flagAddressFile); addressFile != "" {
+				fromFile, err := loadAddressFile(addressFile)
+				if err != nil {
+					return err
+				}
+				addresses = append(addresses, fromFile...)
+			}
+			if len(addresses) == 0 {
+				return fmt.Errorf("--%s or --%s should be set", flagAddress, flagAddressFile)
+			}
+			for i, address := range addresses {
+				addr, err := parseAddress(address)
+				if err != nil {
+					return err
+				}
+				addresses[i] = addr.String()
+			}
+			out := viper.GetString(flagOut)
+			bulk := viper.GetString(flagAddressFile) != ""
+			if bulk && out == "" {
+				return fmt.Errorf("--%s should be set with --%s", flagOut, flagAddressFile)
+			}
+
+			reader, err := openTxHistoryReader(viper.GetString("home"), viper.GetStringSlice(flagTags))
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+
+			if bulk {
+				if err := os.MkdirAll(out, 0755); err != nil {
+					return err
+				}
+			}
+			for _, address := range addresses {
+				history, err := reader.History(address)
+				if err != nil {
+					return err
+				}
+				trace("tx history", address, "txs", len(history.Txs))
+
+				switch {
+				case bulk:
+					err = writeTxHistory(filepath.Join(out, address+".json"), history)
+				case out != "":
+					err = writeTxHistory(out, history)
+				default:
+					encoder := json.NewEncoder(os.Stdout)
+					encoder.SetIndent("", "\t")
+					err = encoder.Encode(history)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			if out != "" {
+				fmt.Println("Transaction history written:", out, "addresses:", len(addresses))
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagAddress, "", "bech32 or hex address")
+	cmd.Flags().String(flagAddressFile, "", "file of addresses, one per line")
+	cmd.Flags().StringSlice(flagTags, []string{"sender", "recipient"}, "indexed tags whose value is an address")
+	cmd.Flags().String(flagOut, "", "output file, or output directory with --address-file")
+	return cmd
+}
+
+func writeTxHistory(name string, history *types.TxHistory) error {
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeJSONFile(file, history)
+}
@@ -0,0 +1,51 @@
+// Package light verifies the commit hash of an export with the light client of
+// Tendermint.
+package light
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/tendermint/lite"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// VerifyBundle verifies the signed headers of the bundle from its trusted
+// validator set, and that the app hash of the final header is the commit hash
+// of the bundle. Each header is verified with the validator set trusted at its
+// height, the next validator set proven by a header is trusted from the next
+// height.
+func VerifyBundle(bundle *types.LightBundle) error {
+	trusted := bundle.Trusted
+	if trusted.ChainID != bundle.ChainID {
+		return fmt.Errorf("trusted validators of chain %s, bundle of chain %s", trusted.ChainID, bundle.ChainID)
+	}
+	if trusted.Validators.IsNilOrEmpty() {
+		return fmt.Errorf("no trusted validators")
+	}
+
+	height, validators := trusted.Height, trusted.Validators
+	commits := append(append([]lite.FullCommit{}, bundle.Transitions...), bundle.Final)
+	for i, fc := range commits {
+		if fc.SignedHeader.Header == nil || fc.SignedHeader.Commit == nil {
+			return fmt.Errorf("signed header %d is incomplete", i)
+		}
+		if err := lite.NewBaseVerifier(bundle.ChainID, height, validators).Verify(fc.SignedHeader); err != nil {
+			return fmt.Errorf("verify header %d: %w", fc.Height(), err)
+		}
+		if err := fc.ValidateFull(bundle.ChainID); err != nil {
+			return fmt.Errorf("verify header %d: %w", fc.Height(), err)
+		}
+		height, validators = fc.Height()+1, fc.NextValidators
+	}
+
+	final := bundle.Final.SignedHeader
+	if final.Height != bundle.CommitID.Version+1 {
+		return fmt.Errorf("final header %d does not carry the app hash of height %d", final.Height, bundle.CommitID.Version)
+	}
+	if !bytes.Equal(final.AppHash, bundle.CommitID.Hash) {
+		return fmt.Errorf("app hash %X of header %d does not match the commit hash %X", final.AppHash, final.Height, bundle.CommitID.Hash)
+	}
+	return nil
+}
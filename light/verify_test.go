@@ -0,0 +1,85 @@
+package light
+
+import (
+	"testing"
+
+	amino "github.com/tendermint/go-amino"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	"github.com/tendermint/tendermint/lite"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+const testChainID = "Binance-Chain-Test"
+
+// testBundle returns a bundle whose validator set changes after height 5, and
+// whose final header of height 11 is signed by the validators first to last.
+func testBundle(first, last int) *types.LightBundle {
+	keys, nextKeys := lite.GenSecpPrivKeys(4), lite.GenSecpPrivKeys(4)
+	validators, nextValidators := keys.ToValidators(1, 0), nextKeys.ToValidators(1, 0)
+	appHash := []byte("app hash of height 10")
+	return &types.LightBundle{
+		ChainID: testChainID,
+		Trusted: types.TrustedValidators{ChainID: testChainID, Height: 1, Validators: validators},
+		Transitions: []lite.FullCommit{
+			keys.GenFullCommit(testChainID, 5, nil, validators, nextValidators, nil, nil, nil, 0, len(keys)),
+		},
+		Final:    nextKeys.GenFullCommit(testChainID, 11, nil, nextValidators, nextValidators, appHash, nil, nil, first, last),
+		CommitID: sdk.CommitID{Version: 10, Hash: appHash},
+	}
+}
+
+func TestVerifyBundle(t *testing.T) {
+	bundle := testBundle(0, 4)
+	if err := VerifyBundle(bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	// the bundle is exchanged in amino JSON
+	cdc := amino.NewCodec()
+	cryptoAmino.RegisterAmino(cdc)
+	data, err := cdc.MarshalJSON(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded types.LightBundle
+	if err := cdc.UnmarshalJSON(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBundle(&decoded); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyBundleRejects(t *testing.T) {
+	bundle := testBundle(0, 4)
+	bundle.CommitID.Hash = []byte("other hash")
+	if err := VerifyBundle(bundle); err == nil {
+		t.Error("commit hash mismatch is verified")
+	}
+
+	bundle = testBundle(0, 4)
+	bundle.CommitID.Version = 11
+	if err := VerifyBundle(bundle); err == nil {
+		t.Error("final header of the commit height is verified")
+	}
+
+	bundle = testBundle(0, 4)
+	bundle.Transitions = nil
+	if err := VerifyBundle(bundle); err == nil {
+		t.Error("validator set change without transition is verified")
+	}
+
+	bundle = testBundle(0, 2)
+	if err := VerifyBundle(bundle); err == nil {
+		t.Error("final header signed by half of the validators is verified")
+	}
+
+	bundle = testBundle(0, 4)
+	bundle.Trusted.Validators = lite.GenSecpPrivKeys(4).ToValidators(1, 0)
+	if err := VerifyBundle(bundle); err == nil {
+		t.Error("bundle of other validators is verified")
+	}
+}
@@ -0,0 +1,165 @@
+package proof
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/node-dump/util"
+)
+
+// The runtime code of MerkleVerifier.sol is compiled with solc 0.8.21, the
+// bytecode depends on the compiler version and the flags.
+//go:generate solc --optimize --optimize-runs 200 --evm-version paris --bin-runtime --overwrite -o testdata testdata/MerkleVerifier.sol
+
+const verifierABI = `[{"type":"function","name":"verify","stateMutability":"pure",
+"inputs":[{"name":"proof","type":"bytes32[]"},{"name":"root","type":"bytes32"},{"name":"account","type":"address"},
+{"name":"symbol","type":"bytes32"},{"name":"amount","type":"uint256"}],
+"outputs":[{"name":"","type":"bool"}]}]`
+
+// evmVerifier is MerkleVerifier deployed in a simulated backend.
+type evmVerifier struct {
+	backend *backends.SimulatedBackend
+	abi     abi.ABI
+	address common.Address
+}
+
+func newEVMVerifier(t *testing.T) *evmVerifier {
+	runtime, err := os.ReadFile("testdata/MerkleVerifier.bin-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := common.FromHex(strings.TrimSpace(string(runtime)))
+
+	// the init code returns the runtime code appended to it
+	initCode := append([]byte{
+		0x61, byte(len(code) >> 8), byte(len(code)), // PUSH2 len
+		0x80,       // DUP1
+		0x60, 0x0c, // PUSH1 12, the length of the init code
+		0x60, 0x00, // PUSH1 0
+		0x39,       // CODECOPY
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+	}, code...)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		auth.From: {Balance: big.NewInt(1e18)},
+	}, 10_000_000)
+	t.Cleanup(func() { backend.Close() })
+
+	ctx := context.Background()
+	nonce, err := backend.PendingNonceAt(ctx, auth.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gasPrice, err := backend.SuggestGasPrice(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := auth.Signer(auth.From, types.NewContractCreation(nonce, big.NewInt(0), 1_000_000, gasPrice, initCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	receipt, err := backend.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("deploy verifier failed")
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(verifierABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &evmVerifier{
+		backend: backend,
+		abi:     parsed,
+		address: receipt.ContractAddress,
+	}
+}
+
+// Verify calls the verifier with the ABI encoded vector.
+func (v *evmVerifier) Verify(t *testing.T, root string, vector *Vector) bool {
+	proof := make([][32]byte, 0, len(vector.Proof))
+	for _, sibling := range vector.Proof {
+		proof = append(proof, common.HexToHash(sibling))
+	}
+	amount, ok := new(big.Int).SetString(vector.Amount, 10)
+	if !ok {
+		t.Fatalf("invalid amount %s", vector.Amount)
+	}
+	data, err := v.abi.Pack("verify", proof, common.HexToHash(root), common.HexToAddress(vector.Address),
+		common.HexToHash(vector.SymbolBytes32), amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := v.backend.CallContract(context.Background(), ethereum.CallMsg{To: &v.address, Data: data}, nil)
+	if err != nil {
+		t.Fatalf("call verifier: %v", err)
+	}
+	result, err := v.abi.Unpack("verify", output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result[0].(bool)
+}
+
+func TestVectorsInEVM(t *testing.T) {
+	sets, err := GenerateTestVectors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := newEVMVerifier(t)
+
+	for _, set := range sets {
+		for _, vector := range set.Vectors {
+			// the vector is consistent with this package
+			leaf := common.FromHex(vector.Leaf)
+			if "0x"+common.Bytes2Hex(crypto.Keccak256(common.FromHex(vector.Encoding))) != vector.Leaf {
+				t.Errorf("%s/%s: leaf is not the hash of the encoding", set.Name, vector.Name)
+			}
+			proof, err := DecodeProof(vector.Proof)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !util.VerifyMerkleProof(common.FromHex(set.Root), proof, leaf) {
+				t.Errorf("%s/%s: proof is invalid", set.Name, vector.Name)
+			}
+
+			if !verifier.Verify(t, set.Root, vector) {
+				t.Errorf("%s/%s: proof is rejected by the verifier", set.Name, vector.Name)
+			}
+
+			// a different amount is rejected
+			tampered := *vector
+			tampered.Amount = "0"
+			if verifier.Verify(t, set.Root, &tampered) {
+				t.Errorf("%s/%s: tampered amount is accepted by the verifier", set.Name, vector.Name)
+			}
+		}
+	}
+}
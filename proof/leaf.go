@@ -0,0 +1,62 @@
+// Package proof builds and verifies the merkle proofs of the exported account
+// balances. A claim of a balance is its leaf and the proof of the leaf against
+// the exported state root.
+package proof
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// SymbolLength is the length of the right padded symbol in the leaf encoding.
+const SymbolLength = 32
+
+// Leaf is the balance of a single denom of an account.
+type Leaf struct {
+	Address sdk.AccAddress `json:"address"`
+	Coin    sdk.Coin       `json:"coin"`
+}
+
+// NewLeaf returns the leaf of the given balance.
+func NewLeaf(address sdk.AccAddress, coin sdk.Coin) *Leaf {
+	return &Leaf{
+		Address: address,
+		Coin:    coin,
+	}
+}
+
+// Encode returns the packed encoding of the leaf: the 20 bytes address, the
+// symbol right padded to 32 bytes and the amount as a 32 bytes big endian
+// integer, the same as `abi.encodePacked(address, bytes32, uint256)`.
+func (leaf *Leaf) Encode() []byte {
+	var symbol [SymbolLength]byte
+	copy(symbol[:], leaf.Coin.Denom)
+
+	data := make([]byte, 0, len(leaf.Address)+SymbolLength+32)
+	data = append(data, leaf.Address.Bytes()...)
+	data = append(data, symbol[:]...)
+	data = append(data, big.NewInt(leaf.Coin.Amount).FillBytes(make([]byte, 32))...)
+	return data
+}
+
+// Hash returns the keccak256 hash of the leaf encoding, it is the leaf of the
+// merkle tree.
+func (leaf *Leaf) Hash() []byte {
+	return crypto.Keccak256(leaf.Encode())
+}
+
+// Serialize implements merkle tree data Serialize method. The tree is built
+// with leaf hashing disabled, so the leaf hash is serialized.
+func (leaf *Leaf) Serialize() ([]byte, error) {
+	return leaf.Hash(), nil
+}
+
+// Print returns the hex encoded hash of the serialized leaf, it is only used
+// in the trace logs.
+func (leaf *Leaf) Print() string {
+	return "0x" + common.Bytes2Hex(crypto.Keccak256(leaf.Hash()))
+}
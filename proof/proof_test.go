@@ -0,0 +1,105 @@
+package proof
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// the leaf encoding and the root are pinned, the published state roots and
+// proofs are only valid as long as they do not change
+const (
+	testLeafEncoding = "0101010101010101010101010101010101010101" +
+		"424e420000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000005f5e100"
+	testLeafHash = "c1a36b25580d6c356ecab87caecb803b3a21edf0a27399e3526dbe44ecf60473"
+	testRoot     = "0x086161bae6254bcf2d99de4dcce3b4136d91b04e847f0922e224a307c42de9a6"
+)
+
+func testAddress(b byte) sdk.AccAddress {
+	return sdk.AccAddress(bytes.Repeat([]byte{b}, 20))
+}
+
+func testLeaves() []*Leaf {
+	return []*Leaf{
+		NewLeaf(testAddress(0x01), sdk.NewCoin("BNB", 100000000)),
+		NewLeaf(testAddress(0x01), sdk.NewCoin("BUSD-BD1", 2500000000)),
+		NewLeaf(testAddress(0x02), sdk.NewCoin("BNB", 1)),
+		NewLeaf(testAddress(0x03), sdk.NewCoin("BTCB-1DE", 9000000000000000000)),
+		NewLeaf(testAddress(0xff), sdk.NewCoin("XRP-BF2", 42)),
+	}
+}
+
+func TestLeafEncoding(t *testing.T) {
+	leaf := testLeaves()[0]
+	if encoding := common.Bytes2Hex(leaf.Encode()); encoding != testLeafEncoding {
+		t.Errorf("leaf encoding changed: %s", encoding)
+	}
+	if hash := common.Bytes2Hex(leaf.Hash()); hash != testLeafHash {
+		t.Errorf("leaf hash changed: %s", hash)
+	}
+	serialized, err := leaf.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(serialized, leaf.Hash()) {
+		t.Error("serialized leaf is not the leaf hash")
+	}
+}
+
+func TestTreeRoot(t *testing.T) {
+	leaves := testLeaves()
+	tree, err := NewTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root := tree.RootHex(); root != testRoot {
+		t.Fatalf("root changed: %s", root)
+	}
+
+	// the root does not depend on the parallel tree building
+	for i := 0; i < 10; i++ {
+		again, err := NewTree(testLeaves())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.RootHex() != testRoot {
+			t.Fatalf("root is not deterministic: %s", again.RootHex())
+		}
+	}
+}
+
+func TestVerifyProofs(t *testing.T) {
+	leaves := testLeaves()
+	tree, err := NewTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, leaf := range leaves {
+		if !Verify(tree.Root(), tree.Proof(i), leaf) {
+			t.Errorf("proof of leaf %d is invalid", i)
+		}
+
+		exported := tree.ExportedProof(i)
+		ok, err := VerifyExportedProof(testRoot, exported)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("exported proof of leaf %d is invalid", i)
+		}
+
+		// a different amount is not proven
+		exported.Coin.Amount++
+		if ok, _ := VerifyExportedProof(testRoot, exported); ok {
+			t.Errorf("tampered proof of leaf %d is valid", i)
+		}
+	}
+
+	if _, err := VerifyExportedProof("not hex", tree.ExportedProof(0)); err == nil {
+		t.Error("expected invalid state root")
+	}
+}
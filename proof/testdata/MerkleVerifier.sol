@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// MerkleVerifier verifies the proofs of the exported account balances. Its
+// runtime code compiled by solc 0.8.21 is MerkleVerifier.bin-runtime.
+contract MerkleVerifier {
+    function verify(
+        bytes32[] calldata proof,
+        bytes32 root,
+        address account,
+        bytes32 symbol,
+        uint256 amount
+    ) external pure returns (bool) {
+        bytes32 hash = keccak256(abi.encodePacked(account, symbol, amount));
+        for (uint256 i = 0; i < proof.length; i++) {
+            bytes32 sibling = proof[i];
+            if (hash < sibling) {
+                hash = keccak256(abi.encodePacked(hash, sibling));
+            } else {
+                hash = keccak256(abi.encodePacked(sibling, hash));
+            }
+        }
+        return hash == root;
+    }
+}
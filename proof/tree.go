@@ -0,0 +1,121 @@
+package proof
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	mt "github.com/txaty/go-merkletree"
+
+	"github.com/bnb-chain/node-dump/types"
+	"github.com/bnb-chain/node-dump/util"
+)
+
+// HashFunc is the hash function of the merkle tree.
+func HashFunc(data []byte) ([]byte, error) {
+	return crypto.Keccak256(data), nil
+}
+
+// Config returns the merkle tree config of the exported state: keccak256
+// hashes of sorted sibling pairs over the leaf hashes.
+func Config() *mt.Config {
+	return &mt.Config{
+		HashFunc:           HashFunc,
+		RunInParallel:      true,
+		SortSiblingPairs:   true,
+		DisableLeafHashing: true,
+	}
+}
+
+// Tree is the merkle tree of the account balances.
+type Tree struct {
+	Leaves []*Leaf
+
+	tree *mt.MerkleTree
+}
+
+// NewTree builds the merkle tree of the given leaves, the proof of each leaf
+// is generated as the tree is built. At least two leaves are required.
+func NewTree(leaves []*Leaf) (*Tree, error) {
+	blocks := make([]mt.DataBlock, 0, len(leaves))
+	for _, leaf := range leaves {
+		blocks = append(blocks, leaf)
+	}
+	tree, err := mt.New(Config(), blocks)
+	if err != nil {
+		return nil, err
+	}
+	return &Tree{
+		Leaves: leaves,
+		tree:   tree,
+	}, nil
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() []byte {
+	return t.tree.Root
+}
+
+// RootHex returns the 0x prefixed hex encoded root of the tree, it is the state
+// root of the export.
+func (t *Tree) RootHex() string {
+	return "0x" + common.Bytes2Hex(t.tree.Root)
+}
+
+// Proof returns the sibling hashes from the leaf at index to the root.
+func (t *Tree) Proof(index int) [][]byte {
+	return t.tree.Proofs[index].Siblings
+}
+
+// ExportedProof returns the exported proof of the leaf at index.
+func (t *Tree) ExportedProof(index int) *types.ExportedProof {
+	leaf := t.Leaves[index]
+	return &types.ExportedProof{
+		Address: leaf.Address,
+		Coin:    leaf.Coin,
+		Proof:   EncodeProof(t.Proof(index)),
+	}
+}
+
+// EncodeProof hex encodes the sibling hashes of a proof.
+func EncodeProof(proof [][]byte) []string {
+	encoded := make([]string, 0, len(proof))
+	for _, sibling := range proof {
+		encoded = append(encoded, "0x"+common.Bytes2Hex(sibling))
+	}
+	return encoded
+}
+
+// DecodeProof decodes the hex encoded sibling hashes of a proof.
+func DecodeProof(proof []string) ([][]byte, error) {
+	decoded := make([][]byte, 0, len(proof))
+	for _, sibling := range proof {
+		data, err := hexutil.Decode(sibling)
+		if err != nil {
+			return nil, fmt.Errorf("decode proof: %w", err)
+		}
+		decoded = append(decoded, data)
+	}
+	return decoded, nil
+}
+
+// Verify reports whether proof proves the leaf against root.
+func Verify(root []byte, proof [][]byte, leaf *Leaf) bool {
+	return util.VerifyMerkleProof(root, proof, leaf.Hash())
+}
+
+// VerifyExportedProof reports whether the exported proof proves its balance
+// against the hex encoded state root.
+func VerifyExportedProof(stateRoot string, exported *types.ExportedProof) (bool, error) {
+	root, err := hexutil.Decode(stateRoot)
+	if err != nil {
+		return false, fmt.Errorf("decode state root: %w", err)
+	}
+	proof, err := DecodeProof(exported.Proof)
+	if err != nil {
+		return false, err
+	}
+	return Verify(root, proof, NewLeaf(exported.Address, exported.Coin)), nil
+}
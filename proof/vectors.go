@@ -0,0 +1,126 @@
+package proof
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Vector is a leaf of a test vector set with its encoding, hash and proof, the
+// byte strings are 0x prefixed hex.
+type Vector struct {
+	Name          string   `json:"name"`
+	Address       string   `json:"address"`
+	Symbol        string   `json:"symbol"`
+	SymbolBytes32 string   `json:"symbol_bytes32"`
+	Amount        string   `json:"amount"`
+	Encoding      string   `json:"encoding"`
+	Leaf          string   `json:"leaf"`
+	Proof         []string `json:"proof"`
+}
+
+// VectorSet is a tree of test vectors.
+type VectorSet struct {
+	Name    string    `json:"name"`
+	Root    string    `json:"root"`
+	Vectors []*Vector `json:"vectors"`
+}
+
+type namedLeaf struct {
+	name string
+	leaf *Leaf
+}
+
+// GenerateTestVectors returns the test vectors of the leaf and proof encoding,
+// for the implementations of the verification outside of this package, e.g.
+// the recovery contract.
+func GenerateTestVectors() ([]*VectorSet, error) {
+	sets := []struct {
+		name   string
+		leaves []namedLeaf
+	}{
+		{
+			name: "minimal",
+			leaves: []namedLeaf{
+				{"first", NewLeaf(vectorAddress(0x01), sdk.NewCoin("BNB", 1))},
+				{"second", NewLeaf(vectorAddress(0x02), sdk.NewCoin("BNB", 2))},
+			},
+		},
+		{
+			name: "odd",
+			leaves: []namedLeaf{
+				{"first", NewLeaf(vectorAddress(0x01), sdk.NewCoin("BNB", 100000000))},
+				{"second", NewLeaf(vectorAddress(0x02), sdk.NewCoin("BUSD-BD1", 2500000000))},
+				{"unpaired", NewLeaf(vectorAddress(0x03), sdk.NewCoin("BTCB-1DE", 12345))},
+			},
+		},
+		{
+			name: "edge cases",
+			leaves: []namedLeaf{
+				{"max length symbol", NewLeaf(vectorAddress(0x11), sdk.NewCoin("ABCDEFGHIJKLMNOPQRSTUVWXYZ-01234", 1))},
+				{"one char symbol", NewLeaf(vectorAddress(0x12), sdk.NewCoin("A", 1))},
+				{"mini token symbol", NewLeaf(vectorAddress(0x13), sdk.NewCoin("XYZ-000M", 5))},
+				{"zero padded address", NewLeaf(zeroPaddedAddress(0x01), sdk.NewCoin("BNB", 10))},
+				{"leading zero address", NewLeaf(sdk.AccAddress(common.FromHex("0x0000000000000000000000000000000000abcdef")), sdk.NewCoin("BNB", 10))},
+				{"max address", NewLeaf(vectorAddress(0xff), sdk.NewCoin("BNB", 10))},
+				{"max amount", NewLeaf(vectorAddress(0x14), sdk.NewCoin("BNB", math.MaxInt64))},
+				{"amount above 2^53", NewLeaf(vectorAddress(0x15), sdk.NewCoin("BNB", 1<<53+1))},
+				{"minimal amount", NewLeaf(vectorAddress(0x16), sdk.NewCoin("BNB", 1))},
+				{"same address other denom", NewLeaf(vectorAddress(0x16), sdk.NewCoin("ETH-1C9", 3))},
+			},
+		},
+	}
+
+	vectorSets := make([]*VectorSet, 0, len(sets))
+	for _, set := range sets {
+		leaves := make([]*Leaf, 0, len(set.leaves))
+		for _, named := range set.leaves {
+			leaves = append(leaves, named.leaf)
+		}
+		tree, err := NewTree(leaves)
+		if err != nil {
+			return nil, err
+		}
+
+		vectorSet := &VectorSet{
+			Name:    set.name,
+			Root:    tree.RootHex(),
+			Vectors: make([]*Vector, 0, len(leaves)),
+		}
+		for i, named := range set.leaves {
+			vectorSet.Vectors = append(vectorSet.Vectors, NewVector(named.name, named.leaf, tree.Proof(i)))
+		}
+		vectorSets = append(vectorSets, vectorSet)
+	}
+	return vectorSets, nil
+}
+
+// NewVector returns the test vector of the leaf and its proof.
+func NewVector(name string, leaf *Leaf, proof [][]byte) *Vector {
+	var symbol [SymbolLength]byte
+	copy(symbol[:], leaf.Coin.Denom)
+	return &Vector{
+		Name:          name,
+		Address:       common.BytesToAddress(leaf.Address).Hex(),
+		Symbol:        leaf.Coin.Denom,
+		SymbolBytes32: "0x" + common.Bytes2Hex(symbol[:]),
+		Amount:        strconv.FormatInt(leaf.Coin.Amount, 10),
+		Encoding:      "0x" + common.Bytes2Hex(leaf.Encode()),
+		Leaf:          "0x" + common.Bytes2Hex(leaf.Hash()),
+		Proof:         EncodeProof(proof),
+	}
+}
+
+func vectorAddress(b byte) sdk.AccAddress {
+	return sdk.AccAddress(bytes.Repeat([]byte{b}, 20))
+}
+
+func zeroPaddedAddress(b byte) sdk.AccAddress {
+	addr := make(sdk.AccAddress, 20)
+	addr[19] = b
+	return addr
+}
@@ -0,0 +1,448 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/node-dump/snapshot/internal/flate"
+)
+
+// ProgressFile is the name of the file in the extraction directory that
+// records the progress of an interrupted extraction.
+const ProgressFile = ".dump-extract-progress.json"
+
+type extractProgress struct {
+	// Segment is the segment the extraction resumes from, SegmentStart its
+	// offset in the archive.
+	Segment      int   `json:"segment"`
+	SegmentStart int64 `json:"segmentStart"`
+	// Offset is the offset in the archive of a block boundary of its DEFLATE
+	// stream and SHA256 the state of the hash of the archive up to Offset.
+	Offset  int64             `json:"offset"`
+	SHA256  []byte            `json:"sha256"`
+	Inflate *flate.Checkpoint `json:"inflate"`
+	// CRC32 and Size are those of the output of the gzip member up to Offset.
+	CRC32 uint32 `json:"crc32"`
+	Size  uint32 `json:"size"`
+	// Entries is the number of archive entries extracted before Entry, the
+	// regular file being written at Offset, of which Written bytes are written.
+	Entries int64       `json:"entries"`
+	Entry   *tar.Header `json:"entry"`
+	Written int64       `json:"written"`
+}
+
+// entryReader reads the data of the regular file being extracted and counts
+// the bytes written.
+type entryReader struct {
+	r       io.Reader
+	header  *tar.Header
+	target  string
+	written int64
+}
+
+func (r *entryReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.written += int64(n)
+	return n, err
+}
+
+// Extractor gunzips and untars a segmented archive into a directory without
+// writing the concatenated archive, hashing the stream on the fly.
+//
+// Once a segment is reached, progress is checkpointed at the next block
+// boundary of the DEFLATE stream within the data of a regular file, with the
+// state of the decompressor and of the hash. A resumed extraction reads the
+// stream again from the start of the segment of its checkpoint, to verify the
+// segment against the manifest, and continues the file being written.
+type Extractor struct {
+	Source     Source
+	Home       string
+	SHA256     string
+	Retries    int
+	RetryDelay time.Duration
+	// Manifest verifies every segment as it is read, it may be nil.
+	Manifest *Manifest
+	// Trace receives progress messages, it may be nil.
+	Trace func(a ...any)
+}
+
+// Extract extracts the archive into the home directory and verifies its SHA256.
+func (e *Extractor) Extract(ctx context.Context) error {
+	if err := os.MkdirAll(e.Home, 0755); err != nil {
+		return err
+	}
+
+	progressPath := filepath.Join(e.Home, ProgressFile)
+	progress, err := loadProgress(progressPath)
+	if err != nil {
+		return err
+	}
+	if progress.Inflate == nil {
+		progress = &extractProgress{}
+	}
+
+	reader := NewSegmentReader(ctx, e.Source, e.Retries, e.RetryDelay)
+	defer reader.Close()
+	reader.Manifest = e.Manifest
+	// starts records the offsets in the archive of the segments reached
+	starts := map[int]int64{progress.Segment: progress.SegmentStart}
+	var done int64
+	reader.OnSegment = func(index int, checksum *SegmentChecksum) error {
+		e.trace("segment extracted:", e.Source.Name(index), "entries", done)
+		starts[index+1] = starts[index] + checksum.Size
+		return nil
+	}
+
+	hasher := sha256.New()
+	if progress.Inflate != nil {
+		e.trace("resume extraction from segment", e.Source.Name(progress.Segment), "entries", progress.Entries)
+		reader.StartAt(progress.Segment)
+		if _, err := io.CopyN(io.Discard, reader, progress.Offset-progress.SegmentStart); err != nil {
+			return noEOF(err)
+		}
+		if err := hasher.(encoding.BinaryUnmarshaler).UnmarshalBinary(progress.SHA256); err != nil {
+			return fmt.Errorf("decode progress file %s: %w", progressPath, err)
+		}
+	}
+	in := newArchiveInput(reader, hasher, progress.Offset)
+
+	var gz *gunzip
+	if progress.Inflate != nil {
+		gz = resumeGunzip(in, progress.Inflate, progress.CRC32, progress.Size)
+		if err := resumeEntry(e.Home, progress.Entry, progress.Written, gz); err != nil {
+			return err
+		}
+		done = progress.Entries + 1
+	} else {
+		gz = newGunzip(in)
+	}
+
+	entry := &entryReader{}
+	var checkpointErr error
+	gz.OnBlock = func() {
+		if checkpointErr != nil || entry.header == nil {
+			return
+		}
+		segment := progress.Segment
+		for start, ok := starts[segment+1]; ok && start <= in.offset; start, ok = starts[segment+1] {
+			segment++
+		}
+		if segment == progress.Segment {
+			return
+		}
+		// the checkpoint must not be ahead of the file on disk
+		if checkpointErr = syncFile(entry.target); checkpointErr != nil {
+			return
+		}
+		state, err := in.Hasher().(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			checkpointErr = err
+			return
+		}
+		checkpoint, crc, size := gz.Checkpoint()
+		next := &extractProgress{
+			Segment:      segment,
+			SegmentStart: starts[segment],
+			Offset:       in.offset,
+			SHA256:       state,
+			Inflate:      checkpoint,
+			CRC32:        crc,
+			Size:         size,
+			Entries:      done,
+			Entry:        entry.header,
+			Written:      entry.written,
+		}
+		if checkpointErr = saveProgress(progressPath, next); checkpointErr == nil {
+			progress = next
+		}
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		*entry = entryReader{r: tr}
+		if header.Typeflag == tar.TypeReg && !isSparse(header) {
+			entry.header = header
+			if entry.target, err = entryPath(e.Home, header.Name); err != nil {
+				return err
+			}
+		}
+		err = extractEntry(e.Home, header, entry)
+		*entry = entryReader{}
+		if checkpointErr != nil {
+			return fmt.Errorf("checkpoint extraction: %w", checkpointErr)
+		}
+		if err != nil {
+			return err
+		}
+		done++
+	}
+
+	// hash the remaining padding of the archive
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.Discard, in); err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(in.Hasher().Sum(nil)); !strings.EqualFold(actual, e.SHA256) {
+		return fmt.Errorf("sha256 mismatch: expected %s, actual %s, the extracted files are not trustworthy", e.SHA256, actual)
+	}
+	if err := os.Remove(progressPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// resumeEntry continues the regular file of header of which written bytes
+// were extracted before the checkpoint, reading the rest of its data and its
+// padding from r.
+func resumeEntry(home string, header *tar.Header, written int64, r io.Reader) error {
+	target, err := entryPath(home, header.Name)
+	if err != nil {
+		return err
+	}
+	if err := checkDirs(home, filepath.Dir(target)); err != nil {
+		return err
+	}
+	info, err := os.Lstat(target)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() || info.Size() < written {
+		return fmt.Errorf("%s changed since the extraction was interrupted, remove %s to start again", target, ProgressFile)
+	}
+
+	// the file was created with the mode of the entry, which may be read only
+	if err := os.Chmod(target, info.Mode().Perm()|0200); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(target, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if err := file.Truncate(written); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Seek(written, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := io.CopyN(file, r, header.Size-written); err != nil {
+		file.Close()
+		return noEOF(err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(target, os.FileMode(header.Mode).Perm()); err != nil {
+		return err
+	}
+	if err := os.Chtimes(target, header.ModTime, header.ModTime); err != nil {
+		return err
+	}
+	// the data of a tar entry is padded to a block of 512 bytes
+	_, err = io.CopyN(io.Discard, r, (512-header.Size%512)%512)
+	return noEOF(err)
+}
+
+// isSparse reports whether the data of a regular file is stored as a sparse
+// map and its fragments, which a checkpoint cannot resume.
+func isSparse(header *tar.Header) bool {
+	for key := range header.PAXRecords {
+		if strings.HasPrefix(key, "GNU.sparse.") {
+			return true
+		}
+	}
+	return false
+}
+
+func syncFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (e *Extractor) trace(a ...any) {
+	if e.Trace != nil {
+		e.Trace(a...)
+	}
+}
+
+// extractEntry writes an archive entry within home. The entries never write
+// through a symbolic link and the symbolic links never point outside home, so
+// an archive cannot write outside home before its SHA256 is checked.
+func extractEntry(home string, header *tar.Header, r io.Reader) error {
+	target, err := entryPath(home, header.Name)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(header.Mode).Perm()
+
+	if header.Typeflag == tar.TypeDir {
+		return makeDirs(home, target, mode|0700)
+	}
+	switch header.Typeflag {
+	case tar.TypeReg, tar.TypeSymlink, tar.TypeLink:
+		if err := makeDirs(home, filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		// replace an existing entry rather than writing through it
+		if info, err := os.Lstat(target); err == nil && !info.IsDir() {
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+		}
+	default:
+		// devices, fifos and extended headers are not part of a node snapshot
+		return nil
+	}
+
+	switch header.Typeflag {
+	case tar.TypeReg:
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, r); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		return os.Chtimes(target, header.ModTime, header.ModTime)
+	case tar.TypeSymlink:
+		linked := header.Linkname
+		if !filepath.IsAbs(linked) {
+			linked = filepath.Join(filepath.Dir(target), linked)
+		}
+		if !withinHome(home, linked) {
+			return fmt.Errorf("archive link %q to %q escapes the target directory", header.Name, header.Linkname)
+		}
+		return os.Symlink(header.Linkname, target)
+	default:
+		source, err := entryPath(home, header.Linkname)
+		if err != nil {
+			return err
+		}
+		if err := checkDirs(home, filepath.Dir(source)); err != nil {
+			return err
+		}
+		return os.Link(source, target)
+	}
+}
+
+// entryPath resolves the name of an archive entry within home.
+func entryPath(home string, name string) (string, error) {
+	target := filepath.Join(home, name)
+	if !withinHome(home, target) {
+		return "", fmt.Errorf("archive entry %q escapes the target directory", name)
+	}
+	return target, nil
+}
+
+// withinHome reports whether the clean path is home or below it.
+func withinHome(home string, path string) bool {
+	home = filepath.Clean(home)
+	path = filepath.Clean(path)
+	return path == home || strings.HasPrefix(path, home+string(os.PathSeparator))
+}
+
+// makeDirs creates the directory dir within home and its missing parents like
+// os.MkdirAll, but fails instead of following a symbolic link below home.
+func makeDirs(home string, dir string, mode os.FileMode) error {
+	return walkDirs(home, dir, func(path string) error {
+		return os.Mkdir(path, mode)
+	})
+}
+
+// checkDirs checks that the directory dir within home and its parents below
+// home exist and are not symbolic links.
+func checkDirs(home string, dir string) error {
+	return walkDirs(home, dir, func(path string) error {
+		return fmt.Errorf("%s does not exist", path)
+	})
+}
+
+// walkDirs checks each component of dir below home, from home down to dir,
+// and calls missing for the components that do not exist.
+func walkDirs(home string, dir string, missing func(path string) error) error {
+	home = filepath.Clean(home)
+	rel, err := filepath.Rel(home, dir)
+	if err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+	path := home
+	for _, name := range strings.Split(rel, string(os.PathSeparator)) {
+		path = filepath.Join(path, name)
+		info, err := os.Lstat(path)
+		switch {
+		case os.IsNotExist(err):
+			if err := missing(path); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case info.Mode()&os.ModeSymlink != 0:
+			return fmt.Errorf("%s is a symbolic link, the archive cannot write through it", path)
+		case !info.IsDir():
+			return fmt.Errorf("%s is not a directory", path)
+		}
+	}
+	return nil
+}
+
+func loadProgress(path string) (*extractProgress, error) {
+	progress := &extractProgress{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, fmt.Errorf("decode progress file %s: %w", path, err)
+	}
+	return progress, nil
+}
+
+func saveProgress(path string, progress *extractProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
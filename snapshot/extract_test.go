@@ -0,0 +1,423 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testArchiveFiles = []struct {
+	name string
+	body string
+}{
+	{"data/", ""},
+	{"data/application.db/000001.ldb", strings.Repeat("application", 500)},
+	{"data/blockstore.db/000002.ldb", strings.Repeat("blockstore", 700)},
+	{"config/config.toml", "moniker = \"dump\"\n"},
+}
+
+// makeArchive builds a tar.gz archive and splits it into segments of size bytes.
+func makeArchive(t *testing.T, size int) ([][]byte, string) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, file := range testArchiveFiles {
+		header := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.body)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(file.name, "/") {
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(file.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	sum := sha256.Sum256(data)
+	segments := make([][]byte, 0)
+	for len(data) > 0 {
+		n := size
+		if n > len(data) {
+			n = len(data)
+		}
+		segments = append(segments, data[:n])
+		data = data[n:]
+	}
+	return segments, hex.EncodeToString(sum[:])
+}
+
+func writeSegments(t *testing.T, segments [][]byte) []string {
+	dir := t.TempDir()
+	paths := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		p := filepath.Join(dir, fmt.Sprintf("part_%02d", i))
+		if err := os.WriteFile(p, segment, 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func checkExtracted(t *testing.T, home string) {
+	for _, file := range testArchiveFiles {
+		if strings.HasSuffix(file.name, "/") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(home, file.name))
+		if err != nil {
+			t.Fatalf("read %s: %v", file.name, err)
+		}
+		if string(data) != file.body {
+			t.Errorf("unexpected content of %s", file.name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(home, ProgressFile)); !os.IsNotExist(err) {
+		t.Errorf("progress file should be removed after extraction")
+	}
+}
+
+func TestExtractLocalSegments(t *testing.T) {
+	segments, sum := makeArchive(t, 100)
+	home := t.TempDir()
+
+	extractor := &Extractor{
+		Source: &LocalSource{Paths: writeSegments(t, segments)},
+		Home:   home,
+		SHA256: sum,
+	}
+	if err := extractor.Extract(context.Background()); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	checkExtracted(t, home)
+}
+
+func TestExtractChecksumMismatch(t *testing.T) {
+	segments, _ := makeArchive(t, 100)
+
+	extractor := &Extractor{
+		Source: &LocalSource{Paths: writeSegments(t, segments)},
+		Home:   t.TempDir(),
+		SHA256: strings.Repeat("0", 64),
+	}
+	err := extractor.Extract(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
+
+// countingSource counts the opens of each segment of a local source.
+type countingSource struct {
+	LocalSource
+	opens map[int]int
+}
+
+func (s *countingSource) Open(ctx context.Context, index int, offset int64) (io.ReadCloser, error) {
+	s.opens[index]++
+	return s.LocalSource.Open(ctx, index, offset)
+}
+
+// makeRandomArchive builds a tar.gz archive of files of random bytes, written
+// as two gzip members, and splits it into segments of size bytes.
+func makeRandomArchive(t *testing.T, size int) ([][]byte, string, map[string][]byte) {
+	rng := rand.New(rand.NewSource(1))
+	files := make(map[string][]byte)
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for i := 0; i < 12; i++ {
+		name := fmt.Sprintf("data/application.db/%06d.ldb", i)
+		body := make([]byte, 50000+rng.Intn(100000))
+		rng.Read(body)
+		files[name] = body
+		// a read only file is continued as well
+		header := &tar.Header{Name: name, Mode: 0444, Size: int64(len(body)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+		if i == 5 {
+			if err := tw.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if err := gw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			gw = gzip.NewWriter(&buf)
+			tw = tar.NewWriter(gw)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	sum := sha256.Sum256(data)
+	var segments [][]byte
+	for len(data) > 0 {
+		n := min(size, len(data))
+		segments = append(segments, data[:n])
+		data = data[n:]
+	}
+	return segments, hex.EncodeToString(sum[:]), files
+}
+
+// failingSource fails to open the segments from index fail.
+type failingSource struct {
+	LocalSource
+	fail int
+}
+
+func (s *failingSource) Open(ctx context.Context, index int, offset int64) (io.ReadCloser, error) {
+	if index >= s.fail {
+		return nil, errors.New("connection refused")
+	}
+	return s.LocalSource.Open(ctx, index, offset)
+}
+
+func TestExtractResume(t *testing.T) {
+	segments, sum, files := makeRandomArchive(t, 64<<10)
+	paths := writeSegments(t, segments)
+	manifest := &Manifest{}
+	for _, path := range paths {
+		checksum, err := FileChecksum(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifest.Segments = append(manifest.Segments, checksum)
+	}
+	home := t.TempDir()
+
+	for _, fail := range []int{len(segments) / 3, 2 * len(segments) / 3} {
+		extractor := &Extractor{
+			Source:   &failingSource{LocalSource: LocalSource{Paths: paths}, fail: fail},
+			Home:     home,
+			SHA256:   sum,
+			Manifest: manifest,
+		}
+		if err := extractor.Extract(context.Background()); err == nil {
+			t.Fatalf("extraction failing at segment %d succeeded", fail)
+		}
+		progress, err := loadProgress(filepath.Join(home, ProgressFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if progress.Segment < fail-2 || progress.Segment >= fail {
+			t.Fatalf("checkpoint at segment %d, interrupted at segment %d", progress.Segment, fail)
+		}
+	}
+	progress, err := loadProgress(filepath.Join(home, ProgressFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := &countingSource{LocalSource: LocalSource{Paths: paths}, opens: make(map[int]int)}
+	extractor := &Extractor{
+		Source:   source,
+		Home:     home,
+		SHA256:   sum,
+		Manifest: manifest,
+	}
+	if err := extractor.Extract(context.Background()); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	// the stream is read again from the segment of the checkpoint
+	for i := range segments {
+		expected := 1
+		if i < progress.Segment {
+			expected = 0
+		}
+		if source.opens[i] != expected {
+			t.Errorf("segment %d opened %d times, expected %d", i, source.opens[i], expected)
+		}
+	}
+	for name, body := range files {
+		path := filepath.Join(home, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, body) {
+			t.Errorf("unexpected content of %s", name)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0444 {
+			t.Errorf("unexpected mode of %s: %v", name, info.Mode())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(home, ProgressFile)); !os.IsNotExist(err) {
+		t.Errorf("progress file should be removed after extraction")
+	}
+}
+
+type testEntry struct {
+	header tar.Header
+	body   string
+}
+
+// makeEntriesArchive builds a tar.gz archive of entries in a single segment.
+func makeEntriesArchive(t *testing.T, entries []testEntry) ([]string, string) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, entry := range entries {
+		header := entry.header
+		header.Size = int64(len(entry.body))
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return writeSegments(t, [][]byte{buf.Bytes()}), hex.EncodeToString(sum[:])
+}
+
+func TestExtractSymlinkEscape(t *testing.T) {
+	outside := t.TempDir()
+
+	for name, entries := range map[string][]testEntry{
+		"absolute link": {
+			{header: tar.Header{Name: "data", Typeflag: tar.TypeSymlink, Linkname: outside}},
+			{header: tar.Header{Name: "data/passwd", Typeflag: tar.TypeReg, Mode: 0644}, body: "owned"},
+		},
+		"relative link": {
+			{header: tar.Header{Name: "config/", Typeflag: tar.TypeDir, Mode: 0755}},
+			{header: tar.Header{Name: "config/data", Typeflag: tar.TypeSymlink, Linkname: "../../" + filepath.Base(outside)}},
+			{header: tar.Header{Name: "config/data/passwd", Typeflag: tar.TypeReg, Mode: 0644}, body: "owned"},
+		},
+	} {
+		paths, sum := makeEntriesArchive(t, entries)
+		extractor := &Extractor{
+			Source: &LocalSource{Paths: paths},
+			Home:   filepath.Join(t.TempDir(), "home"),
+			SHA256: sum,
+		}
+		err := extractor.Extract(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "escapes the target directory") {
+			t.Errorf("%s: expected the link to be rejected, got %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "passwd")); !os.IsNotExist(err) {
+			t.Fatalf("%s: a file was written outside the target directory", name)
+		}
+	}
+}
+
+func TestExtractThroughSymlink(t *testing.T) {
+	home := t.TempDir()
+	outside := t.TempDir()
+	// a link planted in home before the extraction is never followed
+	if err := os.Symlink(outside, filepath.Join(home, "data")); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, sum := makeEntriesArchive(t, []testEntry{
+		{header: tar.Header{Name: "config", Typeflag: tar.TypeSymlink, Linkname: "data"}},
+		{header: tar.Header{Name: "data/passwd", Typeflag: tar.TypeReg, Mode: 0644}, body: "owned"},
+	})
+	extractor := &Extractor{
+		Source: &LocalSource{Paths: paths},
+		Home:   home,
+		SHA256: sum,
+	}
+	err := extractor.Extract(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "symbolic link") {
+		t.Errorf("expected the write through a link to be refused, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "passwd")); !os.IsNotExist(err) {
+		t.Fatal("a file was written through a symbolic link")
+	}
+	// the links within home are kept as links
+	if linked, err := os.Readlink(filepath.Join(home, "config")); err != nil || linked != "data" {
+		t.Errorf("unexpected link %s: %v", linked, err)
+	}
+}
+
+func TestExtractRemoteSegmentsWithBrokenConnection(t *testing.T) {
+	segments, sum := makeArchive(t, 128)
+
+	broken := make(map[string]bool)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var index int
+		if _, err := fmt.Sscanf(r.URL.Path, "/part_%02d", &index); err != nil || index >= len(segments) {
+			http.NotFound(w, r)
+			return
+		}
+		data := segments[index]
+		if !broken[r.URL.Path] && r.Header.Get("Range") == "" {
+			// announce the whole segment but close the connection half way
+			broken[r.URL.Path] = true
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			w.Write(data[:len(data)/2])
+			return
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(data))
+	}))
+	defer ts.Close()
+
+	links := make([]string, 0, len(segments))
+	for i := range segments {
+		links = append(links, fmt.Sprintf("%s/part_%02d", ts.URL, i))
+	}
+
+	fetcher := newTestFetcher(ts.Client())
+	home := t.TempDir()
+	extractor := &Extractor{
+		Source:     &RemoteSource{Fetcher: fetcher, Links: links},
+		Home:       home,
+		SHA256:     sum,
+		Retries:    fetcher.Retries,
+		RetryDelay: fetcher.RetryDelay,
+	}
+	if err := extractor.Extract(context.Background()); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	checkExtracted(t, home)
+}
+
+func TestEntryPathEscape(t *testing.T) {
+	if _, err := entryPath("/tmp/home", "../etc/passwd"); err == nil {
+		t.Error("expected error for an entry outside the target directory")
+	}
+	if p, err := entryPath("/tmp/home", "./data/x"); err != nil || p != "/tmp/home/data/x" {
+		t.Errorf("unexpected entry path %s: %v", p, err)
+	}
+}
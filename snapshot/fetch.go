@@ -0,0 +1,302 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultParallel   = 4
+	defaultRetries    = 5
+	defaultRetryDelay = 3 * time.Second
+
+	partialSuffix = ".part"
+)
+
+// Fetcher downloads snapshot segments in parallel. Interrupted downloads are
+// kept as partial files and resumed with HTTP range requests.
+type Fetcher struct {
+	Client     *http.Client
+	Parallel   int
+	Retries    int
+	RetryDelay time.Duration
+	// Manifest verifies every downloaded segment, it may be nil.
+	Manifest *Manifest
+	// Trace receives progress messages, it may be nil.
+	Trace func(a ...any)
+}
+
+// NewFetcher returns a `Fetcher` with the default settings.
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		Client:     http.DefaultClient,
+		Parallel:   defaultParallel,
+		Retries:    defaultRetries,
+		RetryDelay: defaultRetryDelay,
+	}
+}
+
+// SegmentPath returns the local path of the segment downloaded from link.
+func SegmentPath(dir string, link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return "", fmt.Errorf("invalid segment link: %s", link)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// FetchSegments downloads all segments into dir and returns their local paths
+// in the order of links. Segments that are already complete are skipped.
+func (f *Fetcher) FetchSegments(ctx context.Context, links []string, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(links))
+	for _, link := range links {
+		p, err := SegmentPath(dir, link)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parallel := f.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		jobs     = make(chan int)
+	)
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if err := f.FetchSegment(ctx, links[index], paths[index]); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for index := range links {
+		select {
+		case jobs <- index:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// FetchSegment downloads a single segment to dst, retrying on failure.
+func (f *Fetcher) FetchSegment(ctx context.Context, link string, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		if f.Manifest == nil {
+			f.trace("segment exists, skip:", dst)
+			return nil
+		}
+		err = f.Manifest.VerifyFile(dst)
+		if err == nil {
+			f.trace("segment verified, skip:", dst)
+			return nil
+		}
+		f.trace("segment corrupted, download again:", err)
+		if err := os.Remove(dst); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt <= f.Retries; attempt++ {
+		if attempt > 0 {
+			f.trace("retry segment", link, "attempt", attempt, "error", err)
+			select {
+			case <-time.After(f.RetryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err = f.download(ctx, link, dst+partialSuffix)
+		if err == nil && f.Manifest != nil {
+			err = f.verify(dst+partialSuffix, dst)
+		}
+		if err == nil {
+			f.trace("segment downloaded:", dst)
+			return os.Rename(dst+partialSuffix, dst)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("download %s: %w", link, err)
+}
+
+func (f *Fetcher) download(ctx context.Context, link string, partial string) error {
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	offset := info.Size()
+
+	resp, err := f.request(ctx, link, offset)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// the server ignored the range, start over
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		offset = 0
+	case http.StatusPartialContent:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return fmt.Errorf("unexpected content range %q", resp.Header.Get("Content-Range"))
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file already holds the whole segment
+		if resp.Header.Get("Content-Range") == fmt.Sprintf("bytes */%d", offset) {
+			return nil
+		}
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		return errors.New("partial segment is larger than the remote file")
+	default:
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// verify checks the downloaded partial file of dst against the manifest and
+// removes it when corrupted, so the next attempt downloads it from scratch.
+func (f *Fetcher) verify(partial string, dst string) error {
+	checksum, err := FileChecksum(partial)
+	if err != nil {
+		return err
+	}
+	checksum.Name = filepath.Base(dst)
+	if err := f.Manifest.Verify(checksum); err != nil {
+		os.Remove(partial)
+		return err
+	}
+	return nil
+}
+
+// request sends a GET request for link, asking for the content from offset.
+func (f *Fetcher) request(ctx context.Context, link string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+func (f *Fetcher) trace(a ...any) {
+	if f.Trace != nil {
+		f.Trace(a...)
+	}
+}
+
+// Assemble concatenates the segments in order into dst and verifies the
+// SHA256 of the result. dst is only created when the checksum matches.
+func Assemble(segments []string, dst string, expectedSHA256 string) error {
+	partial := dst + partialSuffix
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	writer := io.MultiWriter(file, hasher)
+	for _, segment := range segments {
+		if err := appendFile(writer, segment); err != nil {
+			return err
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, expectedSHA256) {
+		os.Remove(partial)
+		return fmt.Errorf("sha256 mismatch: expected %s, actual %s", expectedSHA256, actual)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(partial, dst)
+}
+
+func appendFile(w io.Writer, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
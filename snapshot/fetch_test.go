@@ -0,0 +1,181 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type segmentServer struct {
+	segments map[string][]byte
+
+	mu       sync.Mutex
+	requests map[string]int
+	ranges   map[string]string
+	// failFirst makes the first request of each segment fail
+	failFirst bool
+}
+
+func newSegmentServer(count int, size int) *segmentServer {
+	s := &segmentServer{
+		segments: make(map[string][]byte),
+		requests: make(map[string]int),
+		ranges:   make(map[string]string),
+	}
+	for i := 0; i < count; i++ {
+		s.segments[fmt.Sprintf("/part_%02d", i)] = bytes.Repeat([]byte{byte('a' + i)}, size)
+	}
+	return s
+}
+
+func (s *segmentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, exist := s.segments[r.URL.Path]
+	if !exist {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests[r.URL.Path]++
+	first := s.requests[r.URL.Path] == 1
+	if rng := r.Header.Get("Range"); rng != "" {
+		s.ranges[r.URL.Path] = rng
+	}
+	s.mu.Unlock()
+
+	if s.failFirst && first {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(data))
+}
+
+func (s *segmentServer) links(base string) []string {
+	links := make([]string, 0, len(s.segments))
+	for i := 0; i < len(s.segments); i++ {
+		links = append(links, fmt.Sprintf("%s/part_%02d", base, i))
+	}
+	return links
+}
+
+func (s *segmentServer) sha256() string {
+	hasher := sha256.New()
+	for i := 0; i < len(s.segments); i++ {
+		hasher.Write(s.segments[fmt.Sprintf("/part_%02d", i)])
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func newTestFetcher(client *http.Client) *Fetcher {
+	fetcher := NewFetcher()
+	fetcher.Client = client
+	fetcher.RetryDelay = time.Millisecond
+	return fetcher
+}
+
+func TestFetchAndAssemble(t *testing.T) {
+	server := newSegmentServer(8, 1024)
+	server.failFirst = true
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	fetcher := newTestFetcher(ts.Client())
+	paths, err := fetcher.FetchSegments(context.Background(), server.links(ts.URL), filepath.Join(dir, "segments"))
+	if err != nil {
+		t.Fatalf("fetch segments: %v", err)
+	}
+	if len(paths) != 8 {
+		t.Fatalf("expected 8 segments, got %d", len(paths))
+	}
+
+	archive := filepath.Join(dir, "archive.tar.gz")
+	if err := Assemble(paths, archive, server.sha256()); err != nil {
+		t.Fatalf("assemble: %v", err)
+	}
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 8*1024 || data[0] != 'a' || data[len(data)-1] != 'h' {
+		t.Errorf("segments are not assembled in order")
+	}
+}
+
+func TestFetchResume(t *testing.T) {
+	server := newSegmentServer(1, 4096)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "part_00")
+	if err := os.WriteFile(dst+partialSuffix, server.segments["/part_00"][:1000], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher := newTestFetcher(ts.Client())
+	if err := fetcher.FetchSegment(context.Background(), ts.URL+"/part_00", dst); err != nil {
+		t.Fatalf("fetch segment: %v", err)
+	}
+	if rng := server.ranges["/part_00"]; rng != "bytes=1000-" {
+		t.Errorf("expected resume from byte 1000, got range %q", rng)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, server.segments["/part_00"]) {
+		t.Errorf("resumed segment does not match")
+	}
+
+	// completed segments are not downloaded again
+	if err := fetcher.FetchSegment(context.Background(), ts.URL+"/part_00", dst); err != nil {
+		t.Fatalf("fetch segment: %v", err)
+	}
+	if server.requests["/part_00"] != 1 {
+		t.Errorf("expected 1 request, got %d", server.requests["/part_00"])
+	}
+}
+
+func TestFetchGivesUp(t *testing.T) {
+	server := newSegmentServer(1, 16)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	fetcher := newTestFetcher(ts.Client())
+	fetcher.Retries = 2
+	_, err := fetcher.FetchSegments(context.Background(), []string{ts.URL + "/missing"}, t.TempDir())
+	if err == nil {
+		t.Fatal("expected error for missing segment")
+	}
+}
+
+func TestAssembleChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	segment := filepath.Join(dir, "part_00")
+	if err := os.WriteFile(segment, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(dir, "archive.tar.gz")
+	err := Assemble([]string{segment}, archive, strings.Repeat("0", 64))
+	if err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Errorf("archive should not exist after a checksum mismatch")
+	}
+	if _, err := os.Stat(archive + partialSuffix); !os.IsNotExist(err) {
+		t.Errorf("partial archive should be removed after a checksum mismatch")
+	}
+}
@@ -0,0 +1,220 @@
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"github.com/bnb-chain/node-dump/snapshot/internal/flate"
+)
+
+const (
+	gzipFlagHeaderCRC = 1 << 1
+	gzipFlagExtra     = 1 << 2
+	gzipFlagName      = 1 << 3
+	gzipFlagComment   = 1 << 4
+
+	archiveBufferSize = 64 << 10
+)
+
+// archiveInput buffers the compressed archive for the decompressor. It counts
+// and hashes the bytes the decompressor consumed, not the ones buffered ahead,
+// so that the hash of a checkpoint stops at its offset.
+type archiveInput struct {
+	r      io.Reader
+	hasher hash.Hash
+	buf    []byte
+	pos    int
+	hashed int
+	// offset is the position in the archive of the next byte to consume.
+	offset int64
+}
+
+func newArchiveInput(r io.Reader, hasher hash.Hash, offset int64) *archiveInput {
+	return &archiveInput{
+		r:      r,
+		hasher: hasher,
+		buf:    make([]byte, 0, archiveBufferSize),
+		offset: offset,
+	}
+}
+
+func (in *archiveInput) fill() error {
+	in.hasher.Write(in.buf[in.hashed:in.pos])
+	n, err := in.r.Read(in.buf[:cap(in.buf)])
+	in.buf = in.buf[:n]
+	in.pos, in.hashed = 0, 0
+	if n == 0 {
+		if err == nil {
+			err = io.ErrNoProgress
+		}
+		return err
+	}
+	return nil
+}
+
+// ReadByte implements io.ByteReader.
+func (in *archiveInput) ReadByte() (byte, error) {
+	if in.pos == len(in.buf) {
+		if err := in.fill(); err != nil {
+			return 0, err
+		}
+	}
+	c := in.buf[in.pos]
+	in.pos++
+	in.offset++
+	return c, nil
+}
+
+// Read implements io.Reader.
+func (in *archiveInput) Read(p []byte) (int, error) {
+	if in.pos == len(in.buf) {
+		if err := in.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, in.buf[in.pos:])
+	in.pos += n
+	in.offset += int64(n)
+	return n, nil
+}
+
+// Hasher returns the hash of the archive up to offset.
+func (in *archiveInput) Hasher() hash.Hash {
+	in.hasher.Write(in.buf[in.hashed:in.pos])
+	in.hashed = in.pos
+	return in.hasher
+}
+
+// gunzip decompresses the gzip members of the archive. It can resume in the
+// middle of a member from a block boundary of its DEFLATE stream.
+type gunzip struct {
+	in      *archiveInput
+	inflate *flate.Reader
+	members int
+	// crc and size are the CRC32 and size of the output of the member.
+	crc  uint32
+	size uint32
+	// OnBlock is called at each block boundary, see flate.Reader.
+	OnBlock func()
+}
+
+func newGunzip(in *archiveInput) *gunzip {
+	return &gunzip{in: in}
+}
+
+// resumeGunzip continues the member of checkpoint whose output so far has the
+// CRC32 crc and the size size.
+func resumeGunzip(in *archiveInput, checkpoint *flate.Checkpoint, crc uint32, size uint32) *gunzip {
+	z := &gunzip{in: in, members: 1, crc: crc, size: size}
+	z.inflate = flate.Resume(in, checkpoint)
+	z.inflate.OnBlock = z.onBlock
+	return z
+}
+
+func (z *gunzip) onBlock() {
+	if z.OnBlock != nil {
+		z.OnBlock()
+	}
+}
+
+// Checkpoint returns the state of the member at the block boundary OnBlock is
+// called at.
+func (z *gunzip) Checkpoint() (*flate.Checkpoint, uint32, uint32) {
+	return z.inflate.Checkpoint(), z.crc, z.size
+}
+
+// Read implements io.Reader.
+func (z *gunzip) Read(p []byte) (int, error) {
+	for {
+		if z.inflate == nil {
+			err := z.readHeader()
+			if err == io.EOF && z.members > 0 {
+				return 0, io.EOF
+			}
+			if err != nil {
+				return 0, noEOF(err)
+			}
+			z.members++
+			z.crc, z.size = 0, 0
+			z.inflate = flate.NewReader(z.in)
+			z.inflate.OnBlock = z.onBlock
+		}
+
+		n, err := z.inflate.Read(p)
+		z.crc = crc32.Update(z.crc, crc32.IEEETable, p[:n])
+		z.size += uint32(n)
+		if err == io.EOF {
+			if err := z.readTrailer(); err != nil {
+				return n, err
+			}
+			z.inflate = nil
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// readHeader reads the gzip header of the next member, io.EOF when there is
+// none.
+func (z *gunzip) readHeader() error {
+	var header [10]byte
+	if _, err := io.ReadFull(z.in, header[:]); err != nil {
+		return err
+	}
+	if header[0] != 0x1f || header[1] != 0x8b || header[2] != 8 {
+		return gzip.ErrHeader
+	}
+	flags := header[3]
+	if flags&gzipFlagExtra != 0 {
+		var size [2]byte
+		if _, err := io.ReadFull(z.in, size[:]); err != nil {
+			return noEOF(err)
+		}
+		if _, err := io.CopyN(io.Discard, z.in, int64(binary.LittleEndian.Uint16(size[:]))); err != nil {
+			return noEOF(err)
+		}
+	}
+	for _, flag := range []byte{gzipFlagName, gzipFlagComment} {
+		if flags&flag == 0 {
+			continue
+		}
+		for {
+			c, err := z.in.ReadByte()
+			if err != nil {
+				return noEOF(err)
+			}
+			if c == 0 {
+				break
+			}
+		}
+	}
+	if flags&gzipFlagHeaderCRC != 0 {
+		if _, err := io.CopyN(io.Discard, z.in, 2); err != nil {
+			return noEOF(err)
+		}
+	}
+	return nil
+}
+
+func (z *gunzip) readTrailer() error {
+	var trailer [8]byte
+	if _, err := io.ReadFull(z.in, trailer[:]); err != nil {
+		return noEOF(err)
+	}
+	if binary.LittleEndian.Uint32(trailer[:4]) != z.crc || binary.LittleEndian.Uint32(trailer[4:]) != z.size {
+		return gzip.ErrChecksum
+	}
+	return nil
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package flate
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+// testData returns text, repeated runs and random bytes, so that the stream
+// has stored, fixed and dynamic blocks.
+func testData() []byte {
+	rng := rand.New(rand.NewSource(1))
+	var data bytes.Buffer
+	for data.Len() < 1<<20 {
+		switch rng.Intn(3) {
+		case 0:
+			for i := rng.Intn(200); i >= 0; i-- {
+				data.WriteString("the quick brown fox jumps over the lazy dog ")
+			}
+		case 1:
+			data.Write(bytes.Repeat([]byte{byte(rng.Intn(256))}, rng.Intn(5000)))
+		default:
+			random := make([]byte, rng.Intn(20000))
+			rng.Read(random)
+			data.Write(random)
+		}
+	}
+	return data.Bytes()
+}
+
+type testCheckpoint struct {
+	*Checkpoint
+	in  int
+	out int
+}
+
+func TestResume(t *testing.T) {
+	data := testData()
+	for _, level := range []int{flate.NoCompression, flate.BestSpeed, flate.DefaultCompression, flate.BestCompression, flate.HuffmanOnly} {
+		var compressed bytes.Buffer
+		writer, err := flate.NewWriter(&compressed, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write(data)
+		writer.Close()
+
+		input := bytes.NewReader(compressed.Bytes())
+		reader := NewReader(input)
+		var out bytes.Buffer
+		var checkpoints []testCheckpoint
+		reader.OnBlock = func() {
+			checkpoints = append(checkpoints, testCheckpoint{
+				Checkpoint: reader.Checkpoint(),
+				in:         compressed.Len() - input.Len(),
+				out:        out.Len(),
+			})
+		}
+		if _, err := io.Copy(&out, reader); err != nil {
+			t.Fatalf("level %d: %v", level, err)
+		}
+		if !bytes.Equal(out.Bytes(), data) {
+			t.Fatalf("level %d: output differs from the input", level)
+		}
+		if len(checkpoints) < 2 {
+			t.Fatalf("level %d: %d block boundaries", level, len(checkpoints))
+		}
+
+		for _, checkpoint := range checkpoints {
+			resumed, err := io.ReadAll(Resume(bytes.NewReader(compressed.Bytes()[checkpoint.in:]), checkpoint.Checkpoint))
+			if err != nil {
+				t.Fatalf("level %d: resume at %d: %v", level, checkpoint.in, err)
+			}
+			if !bytes.Equal(resumed, data[checkpoint.out:]) {
+				t.Fatalf("level %d: output resumed at %d differs from the input", level, checkpoint.in)
+			}
+		}
+	}
+}
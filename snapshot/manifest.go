@@ -0,0 +1,136 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SegmentChecksum is the size and SHA256 of a single segment.
+type SegmentChecksum struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest lists the checksum of every segment of a snapshot archive.
+type Manifest struct {
+	Network  string             `json:"network"`
+	Archive  string             `json:"archive"`
+	SHA256   string             `json:"sha256"`
+	Segments []*SegmentChecksum `json:"segments"`
+}
+
+// Lookup returns the checksum of the segment with the given name.
+func (m *Manifest) Lookup(name string) (*SegmentChecksum, bool) {
+	for _, segment := range m.Segments {
+		if segment.Name == name {
+			return segment, true
+		}
+	}
+	return nil, false
+}
+
+// Verify checks a segment against the manifest.
+func (m *Manifest) Verify(actual *SegmentChecksum) error {
+	expected, exist := m.Lookup(actual.Name)
+	if !exist {
+		return fmt.Errorf("segment %s is not in the manifest", actual.Name)
+	}
+	if expected.Size != actual.Size {
+		return fmt.Errorf("segment %s size mismatch: expected %d, actual %d", actual.Name, expected.Size, actual.Size)
+	}
+	if !strings.EqualFold(expected.SHA256, actual.SHA256) {
+		return fmt.Errorf("segment %s sha256 mismatch: expected %s, actual %s", actual.Name, expected.SHA256, actual.SHA256)
+	}
+	return nil
+}
+
+// VerifyFile checks a local segment file against the manifest.
+func (m *Manifest) VerifyFile(path string) error {
+	checksum, err := FileChecksum(path)
+	if err != nil {
+		return err
+	}
+	return m.Verify(checksum)
+}
+
+// FileChecksum computes the checksum of a local segment file.
+func FileChecksum(path string) (*SegmentChecksum, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return nil, err
+	}
+	return &SegmentChecksum{
+		Name:   filepath.Base(path),
+		Size:   size,
+		SHA256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// LoadManifest reads a manifest from a JSON file.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// WriteManifest writes a manifest to a JSON file.
+func WriteManifest(path string, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// GenerateManifest reads every segment of source, local or streamed, and
+// records its size and SHA256. The archive checksum must match the published
+// one of the network.
+func GenerateManifest(ctx context.Context, network *Network, source Source, retries int, retryDelay time.Duration, trace func(a ...any)) (*Manifest, error) {
+	manifest := &Manifest{
+		Network:  network.Name,
+		Archive:  network.Archive,
+		SHA256:   network.SHA256,
+		Segments: make([]*SegmentChecksum, 0, source.Len()),
+	}
+
+	reader := NewSegmentReader(ctx, source, retries, retryDelay)
+	defer reader.Close()
+	reader.OnSegment = func(index int, checksum *SegmentChecksum) error {
+		if trace != nil {
+			trace("segment hashed:", checksum.Name, "size", checksum.Size, "sha256", checksum.SHA256)
+		}
+		manifest.Segments = append(manifest.Segments, checksum)
+		return nil
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return nil, err
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, network.SHA256) {
+		return nil, fmt.Errorf("sha256 mismatch: expected %s, actual %s", network.SHA256, actual)
+	}
+	return manifest, nil
+}
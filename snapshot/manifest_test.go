@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateManifest(t *testing.T) {
+	segments, sum := makeArchive(t, 100)
+	paths := writeSegments(t, segments)
+	network := &Network{Name: "testnet", Archive: "archive.tar.gz", SHA256: sum}
+
+	manifest, err := GenerateManifest(context.Background(), network, &LocalSource{Paths: paths}, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("generate manifest: %v", err)
+	}
+	if len(manifest.Segments) != len(paths) {
+		t.Fatalf("expected %d segments, got %d", len(paths), len(manifest.Segments))
+	}
+	for _, p := range paths {
+		if err := manifest.VerifyFile(p); err != nil {
+			t.Errorf("verify %s: %v", p, err)
+		}
+	}
+
+	// a manifest is not generated for an archive with the wrong checksum
+	network.SHA256 = strings.Repeat("0", 64)
+	if _, err := GenerateManifest(context.Background(), network, &LocalSource{Paths: paths}, 0, 0, nil); err == nil {
+		t.Error("expected checksum mismatch")
+	}
+}
+
+func TestFetchRedownloadsCorruptedSegments(t *testing.T) {
+	server := newSegmentServer(4, 512)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	manifest := &Manifest{}
+	for _, link := range server.links("") {
+		data := server.segments[link]
+		checksum, err := FileChecksum(writeSegments(t, [][]byte{data})[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		checksum.Name = strings.TrimPrefix(link, "/")
+		manifest.Segments = append(manifest.Segments, checksum)
+	}
+
+	dir := t.TempDir()
+	fetcher := newTestFetcher(ts.Client())
+	fetcher.Manifest = manifest
+	if _, err := fetcher.FetchSegments(context.Background(), server.links(ts.URL), dir); err != nil {
+		t.Fatalf("fetch segments: %v", err)
+	}
+
+	// corrupt one segment, only that one is downloaded again
+	if err := os.WriteFile(filepath.Join(dir, "part_02"), bytes.Repeat([]byte{'x'}, 512), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetcher.FetchSegments(context.Background(), server.links(ts.URL), dir); err != nil {
+		t.Fatalf("fetch segments: %v", err)
+	}
+	for _, link := range server.links("") {
+		expected := 1
+		if link == "/part_02" {
+			expected = 2
+		}
+		if server.requests[link] != expected {
+			t.Errorf("expected %d requests of %s, got %d", expected, link, server.requests[link])
+		}
+	}
+	if err := manifest.VerifyFile(filepath.Join(dir, "part_02")); err != nil {
+		t.Errorf("segment is not repaired: %v", err)
+	}
+}
+
+func TestSegmentReaderDetectsCorruptedSegment(t *testing.T) {
+	segments, sum := makeArchive(t, 100)
+	paths := writeSegments(t, segments)
+	network := &Network{Name: "testnet", SHA256: sum}
+	manifest, err := GenerateManifest(context.Background(), network, &LocalSource{Paths: paths}, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corrupted := append([]byte{}, segments[1]...)
+	corrupted[0] ^= 0xff
+	if err := os.WriteFile(paths[1], corrupted, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := NewSegmentReader(context.Background(), &LocalSource{Paths: paths}, 0, 0)
+	reader.Manifest = manifest
+	_, err = io.Copy(io.Discard, reader)
+	if err == nil || !strings.Contains(err.Error(), "part_01 sha256 mismatch") {
+		t.Fatalf("expected corrupted segment part_01, got %v", err)
+	}
+}
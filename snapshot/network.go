@@ -0,0 +1,45 @@
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/bnb-chain/node-dump/asset"
+)
+
+// Network describes a published BNB Beacon Chain node snapshot.
+type Network struct {
+	Name     string
+	ChainID  string
+	Archive  string
+	SHA256   string
+	Segments []string
+}
+
+// Networks returns the published snapshots, keyed by network name.
+func Networks() map[string]*Network {
+	return map[string]*Network{
+		"mainnet": {
+			Name:     "mainnet",
+			ChainID:  "Binance-Chain-Tigris",
+			Archive:  "bc-mainnet-dataseed.tar.gz",
+			SHA256:   "da4b5460cf494030403af8e6da8f5399efe5fd06f9aaf754e15105dc93f792bb",
+			Segments: asset.MainnetSegmentLinks(),
+		},
+		"testnet": {
+			Name:     "testnet",
+			ChainID:  "Binance-Chain-Ganges",
+			Archive:  "bc-testnet-dataseed.tar.gz",
+			SHA256:   "777a25f6d3228acb1854f1366b13befc1c2089ae2740cf5757120682ffc79a30",
+			Segments: asset.TestnetSegmentLinks(),
+		},
+	}
+}
+
+// GetNetwork returns the published snapshot of the given network.
+func GetNetwork(name string) (*Network, error) {
+	network, exist := Networks()[name]
+	if !exist {
+		return nil, fmt.Errorf("unknown network %q, expected mainnet or testnet", name)
+	}
+	return network, nil
+}
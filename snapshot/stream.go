@@ -0,0 +1,270 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Source provides the ordered segments of a segmented archive.
+type Source interface {
+	// Len returns the number of segments.
+	Len() int
+	// Name returns a readable name of the segment at index.
+	Name(index int) string
+	// Open opens the segment at index, starting from offset.
+	Open(ctx context.Context, index int, offset int64) (io.ReadCloser, error)
+}
+
+// LocalSource reads the segments from local files.
+type LocalSource struct {
+	Paths []string
+}
+
+// Len implements Source.
+func (s *LocalSource) Len() int {
+	return len(s.Paths)
+}
+
+// Name implements Source.
+func (s *LocalSource) Name(index int) string {
+	return s.Paths[index]
+}
+
+// Open implements Source.
+func (s *LocalSource) Open(_ context.Context, index int, offset int64) (io.ReadCloser, error) {
+	file, err := os.Open(s.Paths[index])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// RemoteSource streams the segments over HTTP without storing them.
+type RemoteSource struct {
+	Fetcher *Fetcher
+	Links   []string
+}
+
+// Len implements Source.
+func (s *RemoteSource) Len() int {
+	return len(s.Links)
+}
+
+// Name implements Source.
+func (s *RemoteSource) Name(index int) string {
+	return s.Links[index]
+}
+
+// Open implements Source.
+func (s *RemoteSource) Open(ctx context.Context, index int, offset int64) (io.ReadCloser, error) {
+	resp, err := s.Fetcher.request(ctx, s.Links[index], offset)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		return resp.Body, nil
+	case resp.StatusCode == http.StatusOK:
+		// the server ignored the range, skip the bytes already read
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		return resp.Body, nil
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+}
+
+// SpoolSource downloads each segment into a spool directory before it is
+// read. The fetcher verifies the segment against its manifest and downloads
+// it again when corrupted, so a bad segment never reaches the reader. Only the
+// segment being read is kept on disk.
+type SpoolSource struct {
+	Fetcher *Fetcher
+	Links   []string
+	Dir     string
+}
+
+// Len implements Source.
+func (s *SpoolSource) Len() int {
+	return len(s.Links)
+}
+
+// Name implements Source.
+func (s *SpoolSource) Name(index int) string {
+	return s.Links[index]
+}
+
+// Open implements Source.
+func (s *SpoolSource) Open(ctx context.Context, index int, offset int64) (io.ReadCloser, error) {
+	spool, err := SegmentPath(s.Dir, s.Links[index])
+	if err != nil {
+		return nil, err
+	}
+	if offset == 0 {
+		if index > 0 {
+			previous, err := SegmentPath(s.Dir, s.Links[index-1])
+			if err != nil {
+				return nil, err
+			}
+			os.Remove(previous)
+		}
+		if err := os.MkdirAll(s.Dir, 0755); err != nil {
+			return nil, err
+		}
+		if err := s.Fetcher.FetchSegment(ctx, s.Links[index], spool); err != nil {
+			return nil, err
+		}
+	}
+	return (&LocalSource{Paths: []string{spool}}).Open(ctx, 0, offset)
+}
+
+// segmentName returns the base name of a segment path or link.
+func segmentName(name string) string {
+	if u, err := url.Parse(name); err == nil && u.Scheme != "" {
+		return path.Base(u.Path)
+	}
+	return filepath.Base(name)
+}
+
+// SegmentReader reads the segments of a Source as one logical stream. A
+// segment that fails while being read is reopened at the failed offset, so a
+// broken connection only costs a retry of the current segment. Each segment is
+// hashed while it is read and checked against the manifest when one is set.
+type SegmentReader struct {
+	ctx        context.Context
+	source     Source
+	retries    int
+	retryDelay time.Duration
+
+	index    int
+	offset   int64
+	current  io.ReadCloser
+	failures int
+	hasher   hash.Hash
+
+	// Manifest verifies every segment when it is fully read, it may be nil.
+	Manifest *Manifest
+	// OnSegment is called after the segment at index is fully read.
+	OnSegment func(index int, checksum *SegmentChecksum) error
+}
+
+// NewSegmentReader returns a reader over all segments of source.
+func NewSegmentReader(ctx context.Context, source Source, retries int, retryDelay time.Duration) *SegmentReader {
+	return &SegmentReader{
+		ctx:        ctx,
+		source:     source,
+		retries:    retries,
+		retryDelay: retryDelay,
+		hasher:     sha256.New(),
+	}
+}
+
+// StartAt starts the stream at the beginning of the segment at index, the
+// segments before it are not read. It must be called before the first Read.
+func (r *SegmentReader) StartAt(index int) {
+	r.index = index
+}
+
+// Read implements io.Reader.
+func (r *SegmentReader) Read(p []byte) (int, error) {
+	for {
+		if r.index >= r.source.Len() {
+			return 0, io.EOF
+		}
+
+		if r.current == nil {
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := r.current.Read(p)
+		r.offset += int64(n)
+		if n > 0 {
+			r.failures = 0
+			r.hasher.Write(p[:n])
+		}
+		switch {
+		case err == io.EOF:
+			r.current.Close()
+			r.current = nil
+			checksum := &SegmentChecksum{
+				Name:   segmentName(r.source.Name(r.index)),
+				Size:   r.offset,
+				SHA256: hex.EncodeToString(r.hasher.Sum(nil)),
+			}
+			if r.Manifest != nil {
+				if err := r.Manifest.Verify(checksum); err != nil {
+					return n, err
+				}
+			}
+			if r.OnSegment != nil {
+				if err := r.OnSegment(r.index, checksum); err != nil {
+					return n, err
+				}
+			}
+			r.index++
+			r.offset = 0
+			r.hasher.Reset()
+		case err != nil:
+			// reopen the segment at the current offset on the next read
+			r.current.Close()
+			r.current = nil
+			r.failures++
+			if r.failures > r.retries {
+				return n, fmt.Errorf("read segment %s at %d: %w", r.source.Name(r.index), r.offset, err)
+			}
+		}
+		if n > 0 {
+			return n, nil
+		}
+		if err := r.ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (r *SegmentReader) open() (err error) {
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(r.retryDelay * time.Duration(attempt)):
+			case <-r.ctx.Done():
+				return r.ctx.Err()
+			}
+		}
+		r.current, err = r.source.Open(r.ctx, r.index, r.offset)
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("open segment %s at %d: %w", r.source.Name(r.index), r.offset, err)
+}
+
+// Close closes the segment being read.
+func (r *SegmentReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
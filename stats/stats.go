@@ -0,0 +1,163 @@
+// Package stats computes the distribution of the denoms among the holders of
+// the exported accounts.
+package stats
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node-dump/types"
+)
+
+// Percentiles are the percentiles of the balances reported for each denom.
+var Percentiles = []float64{50, 90, 99, 99.9}
+
+// holderHeap is a min heap of holders by amount, it keeps the top holders.
+type holderHeap []*types.Holder
+
+func (h holderHeap) Len() int           { return len(h) }
+func (h holderHeap) Less(i, j int) bool { return h[i].Amount < h[j].Amount }
+func (h holderHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *holderHeap) Push(x any)        { *h = append(*h, x.(*types.Holder)) }
+func (h *holderHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+type denomCollector struct {
+	amounts []int64
+	total   int64
+	top     holderHeap
+}
+
+// Collector collects the balances of the accounts one at a time, it keeps the
+// balances of each denom without the addresses but of the top holders.
+type Collector struct {
+	topN          int
+	accounts      int64
+	emptyAccounts int64
+	denoms        map[string]*denomCollector
+}
+
+// NewCollector returns a collector keeping the topN holders of each denom.
+func NewCollector(topN int) *Collector {
+	return &Collector{
+		topN:   topN,
+		denoms: make(map[string]*denomCollector),
+	}
+}
+
+// Add adds the balances of an account.
+func (c *Collector) Add(addr sdk.AccAddress, coins sdk.Coins) {
+	c.accounts++
+	empty := true
+	for _, coin := range coins {
+		if coin.Amount <= 0 {
+			continue
+		}
+		empty = false
+		denom, ok := c.denoms[coin.Denom]
+		if !ok {
+			denom = &denomCollector{}
+			c.denoms[coin.Denom] = denom
+		}
+		denom.amounts = append(denom.amounts, coin.Amount)
+		denom.total += coin.Amount
+		if c.topN <= 0 {
+			continue
+		}
+		if len(denom.top) < c.topN {
+			heap.Push(&denom.top, &types.Holder{Address: addr, Amount: coin.Amount})
+		} else if coin.Amount > denom.top[0].Amount {
+			denom.top[0] = &types.Holder{Address: addr, Amount: coin.Amount}
+			heap.Fix(&denom.top, 0)
+		}
+	}
+	if empty {
+		c.emptyAccounts++
+	}
+}
+
+// Stats returns the distributions of the denoms sorted by denom.
+func (c *Collector) Stats() *types.HolderStats {
+	stats := &types.HolderStats{
+		Accounts:      c.accounts,
+		EmptyAccounts: c.emptyAccounts,
+		Denoms:        make([]*types.DenomStats, 0, len(c.denoms)),
+	}
+	for name, denom := range c.denoms {
+		stats.Denoms = append(stats.Denoms, denom.stats(name))
+	}
+	sort.Slice(stats.Denoms, func(i, j int) bool {
+		return stats.Denoms[i].Denom < stats.Denoms[j].Denom
+	})
+	return stats
+}
+
+func (d *denomCollector) stats(name string) *types.DenomStats {
+	amounts := d.amounts
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i] < amounts[j] })
+	n := len(amounts)
+
+	stats := &types.DenomStats{
+		Denom:       name,
+		Holders:     int64(n),
+		Total:       d.total,
+		Top:         make([]*types.Holder, len(d.top)),
+		Percentiles: make([]*types.Percentile, 0, len(Percentiles)),
+		Top1Share:   topShare(amounts, d.total, 0.01),
+		Top10Share:  topShare(amounts, d.total, 0.1),
+		Gini:        gini(amounts, d.total),
+	}
+	copy(stats.Top, d.top)
+	sort.Slice(stats.Top, func(i, j int) bool { return stats.Top[i].Amount > stats.Top[j].Amount })
+	for _, p := range Percentiles {
+		stats.Percentiles = append(stats.Percentiles, &types.Percentile{P: p, Amount: percentile(amounts, p)})
+	}
+	return stats
+}
+
+// percentile returns the nearest rank percentile p of the sorted amounts.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// topShare returns the share of total held by the fraction of the holders of
+// the largest sorted amounts, at least one holder.
+func topShare(sorted []int64, total int64, fraction float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	count := int(math.Ceil(fraction * float64(len(sorted))))
+	var held float64
+	for _, amount := range sorted[len(sorted)-count:] {
+		held += float64(amount)
+	}
+	return held / float64(total)
+}
+
+// gini returns the Gini coefficient of the sorted amounts, 0 when they are
+// equal and close to 1 when a holder holds almost everything.
+func gini(sorted []int64, total int64) float64 {
+	n := float64(len(sorted))
+	if n == 0 || total == 0 {
+		return 0
+	}
+	var weighted float64
+	for i, amount := range sorted {
+		weighted += float64(i+1) * float64(amount)
+	}
+	return 2*weighted/(n*float64(total)) - (n+1)/n
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func testAddress(i byte) sdk.AccAddress {
+	addr := make([]byte, 20)
+	addr[19] = i
+	return addr
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(2)
+	for i := 1; i <= 10; i++ {
+		c.Add(testAddress(byte(i)), sdk.Coins{{Denom: "BNB", Amount: int64(i)}})
+	}
+	c.Add(testAddress(11), sdk.Coins{{Denom: "BNB", Amount: 0}})
+	c.Add(testAddress(12), nil)
+	c.Add(testAddress(13), sdk.Coins{{Denom: "XYZ-000", Amount: 100}})
+
+	stats := c.Stats()
+	if stats.Accounts != 13 || stats.EmptyAccounts != 2 {
+		t.Fatalf("accounts %d empty %d", stats.Accounts, stats.EmptyAccounts)
+	}
+	if len(stats.Denoms) != 2 || stats.Denoms[0].Denom != "BNB" {
+		t.Fatalf("denoms %v", stats.Denoms)
+	}
+	bnb := stats.Denoms[0]
+	if bnb.Holders != 10 || bnb.Total != 55 {
+		t.Errorf("holders %d total %d", bnb.Holders, bnb.Total)
+	}
+	if len(bnb.Top) != 2 || bnb.Top[0].Amount != 10 || bnb.Top[1].Amount != 9 || !bnb.Top[0].Address.Equals(testAddress(10)) {
+		t.Errorf("top %v", bnb.Top)
+	}
+	if bnb.Percentiles[0].Amount != 5 || bnb.Percentiles[1].Amount != 9 || bnb.Percentiles[3].Amount != 10 {
+		t.Errorf("percentiles %v %v %v", bnb.Percentiles[0], bnb.Percentiles[1], bnb.Percentiles[3])
+	}
+	if math.Abs(bnb.Top10Share-10.0/55) > 1e-9 {
+		t.Errorf("top 10%% share %f", bnb.Top10Share)
+	}
+	// the Gini coefficient of 1..n is (n-1)/(3n)
+	if math.Abs(bnb.Gini-9.0/30) > 1e-9 {
+		t.Errorf("gini %f", bnb.Gini)
+	}
+
+	xyz := stats.Denoms[1]
+	if xyz.Holders != 1 || xyz.Gini != 0 || xyz.Top1Share != 1 {
+		t.Errorf("single holder %+v", xyz)
+	}
+}
@@ -4,11 +4,47 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-// ExportedAccount is an exported account.
+// ExportedAccount is an exported account. Coins is the total of the available
+// coins, the coins frozen by the owner and the coins locked in open orders.
 type ExportedAccount struct {
-	Address       sdk.AccAddress `json:"address"`
-	AccountNumber int64          `json:"account_number"`
-	Coins         sdk.Coins      `json:"coins,omitempty"`
+	Address       sdk.AccAddress  `json:"address"`
+	AccountNumber int64           `json:"account_number"`
+	Sequence      int64           `json:"sequence"`
+	PubKey        *ExportedPubKey `json:"pub_key,omitempty"`
+	Coins         sdk.Coins       `json:"coins,omitempty"`
+	Available     sdk.Coins       `json:"available,omitempty"`
+	Frozen        sdk.Coins       `json:"frozen,omitempty"`
+	Locked        sdk.Coins       `json:"locked,omitempty"`
+}
+
+// AccountDetail is an account at the block height with the fields of the named
+// account, and its proofs in an export. ProofRoot is the state root of the
+// proofs.
+type AccountDetail struct {
+	ChainID     string `json:"chain_id"`
+	BlockHeight int64  `json:"block_height"`
+	ExportedAccount
+	Name      string           `json:"name,omitempty"`
+	Flags     uint64           `json:"flags"`
+	ProofRoot string           `json:"proof_root,omitempty"`
+	Proofs    []*ExportedProof `json:"proofs,omitempty"`
+}
+
+// ExportedPubKey is the public key of an account that has signed a
+// transaction.
+type ExportedPubKey struct {
+	Type string `json:"type"`
+	Key  []byte `json:"key"`
+}
+
+// AccountSummary counts the exported accounts holding a nonzero balance, and
+// sums their frozen and locked coins.
+type AccountSummary struct {
+	Holders              int64     `json:"holders"`
+	HoldersWithPubKey    int64     `json:"holders_with_pub_key"`
+	HoldersWithoutPubKey int64     `json:"holders_without_pub_key"`
+	Frozen               sdk.Coins `json:"frozen"`
+	Locked               sdk.Coins `json:"locked"`
 }
 
 // ExportedProof is an exported proof.
@@ -25,5 +61,21 @@ type ExportedAccountState struct {
 	CommitID    sdk.CommitID       `json:"commit_id"`
 	Accounts    []*ExportedAccount `json:"-"`
 	StateRoot   string             `json:"state_root"`
+	Summary     *AccountSummary    `json:"summary,omitempty"`
+	Excluded    []*ExcludedAccount `json:"excluded,omitempty"`
 	Proofs      []*ExportedProof   `json:"-"`
 }
+
+// ExcludedAccount is an account left out of the export, e.g. the escrow
+// account of a module.
+type ExcludedAccount struct {
+	Address sdk.AccAddress `json:"address"`
+	Label   string         `json:"label"`
+	Reason  string         `json:"reason"`
+}
+
+// ExcludedBalance is the balance of an excluded account at the exported height.
+type ExcludedBalance struct {
+	ExcludedAccount
+	Coins sdk.Coins `json:"coins"`
+}
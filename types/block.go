@@ -0,0 +1,41 @@
+package types
+
+import (
+	"encoding/json"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// ExportedBlock is a block of the Tendermint block store without its
+// transactions. Header, Commit and Validators are the amino JSON encodings of
+// the header, of the commit signed by the validators and of the validator set
+// at the height.
+type ExportedBlock struct {
+	Height     int64           `json:"height"`
+	Hash       cmn.HexBytes    `json:"hash"`
+	Header     json.RawMessage `json:"header"`
+	Commit     json.RawMessage `json:"commit"`
+	Validators json.RawMessage `json:"validators"`
+}
+
+// AppHashCheck compares the hash of the last commit of the app with the app
+// hash recorded by Tendermint. The app hash of a height is in the header of the
+// next height, Source is header when that header is in the block store, and
+// state when the app hash is the one of the state DB.
+type AppHashCheck struct {
+	Height       int64        `json:"height"`
+	CommitHash   cmn.HexBytes `json:"commit_hash"`
+	Source       string       `json:"source"`
+	HeaderHeight int64        `json:"header_height,omitempty"`
+	AppHash      cmn.HexBytes `json:"app_hash"`
+	Match        bool         `json:"match"`
+}
+
+// ExportedBlocks are the blocks from From to To of the block store.
+type ExportedBlocks struct {
+	ChainID      string           `json:"chain_id"`
+	From         int64            `json:"from"`
+	To           int64            `json:"to"`
+	Blocks       []*ExportedBlock `json:"blocks"`
+	AppHashCheck AppHashCheck     `json:"app_hash_check"`
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+)
+
+// ExportedProposal is a governance proposal with its recorded deposits and
+// votes. The votes and deposits are removed from the store when the voting
+// ends, Tally is then the final tally recorded in the proposal, and the
+// current tally of the votes while the proposal is in its voting period.
+type ExportedProposal struct {
+	SideChainID     string             `json:"side_chain_id,omitempty"`
+	ID              int64              `json:"id"`
+	Type            gov.ProposalKind   `json:"type"`
+	Title           string             `json:"title"`
+	Description     string             `json:"description"`
+	Status          gov.ProposalStatus `json:"status"`
+	SubmitTime      time.Time          `json:"submit_time"`
+	TotalDeposit    sdk.Coins          `json:"total_deposit"`
+	VotingStartTime time.Time          `json:"voting_start_time"`
+	VotingPeriod    time.Duration      `json:"voting_period"`
+	Tally           gov.TallyResult    `json:"tally"`
+	Deposits        []gov.Deposit      `json:"deposits"`
+	Votes           []gov.Vote         `json:"votes"`
+}
+
+// ExportedGov are the governance proposals of the Beacon Chain and of the side
+// chains at the block height.
+type ExportedGov struct {
+	ChainID     string              `json:"chain_id"`
+	BlockHeight int64               `json:"block_height"`
+	Proposals   []*ExportedProposal `json:"proposals"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/tendermint/tendermint/lite"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TrustedValidators is the validator set trusted at a height, the anchor of
+// the light client verification.
+type TrustedValidators struct {
+	ChainID    string                `json:"chain_id"`
+	Height     int64                 `json:"height"`
+	Validators *tmtypes.ValidatorSet `json:"validators"`
+}
+
+// LightBundle proves the commit hash of an export from a trusted validator
+// set. Transitions are the signed headers of the heights after which the
+// validator set changes, Final is the signed header of the height after the
+// commit, whose app hash is the commit hash. The bundle is encoded in amino
+// JSON.
+type LightBundle struct {
+	ChainID     string            `json:"chain_id"`
+	Trusted     TrustedValidators `json:"trusted"`
+	Transitions []lite.FullCommit `json:"transitions"`
+	Final       lite.FullCommit   `json:"final"`
+	CommitID    sdk.CommitID      `json:"commit_id"`
+}
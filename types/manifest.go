@@ -0,0 +1,28 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// ExportedFile is the size and SHA256 of an exported file.
+type ExportedFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ExportManifest binds the exported files to the exported account state.
+type ExportManifest struct {
+	Version string               `json:"version"`
+	State   ExportedAccountState `json:"state"`
+	Files   []*ExportedFile      `json:"files"`
+}
+
+// SignedExportManifest is an export manifest signed by the operator. The
+// signature covers the compact JSON encoding of the manifest.
+type SignedExportManifest struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	KeyType   string          `json:"key_type,omitempty"`
+	PubKey    []byte          `json:"pub_key,omitempty"`
+	Signature []byte          `json:"signature,omitempty"`
+}
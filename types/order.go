@@ -0,0 +1,35 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ExportedOrder is an open order of the DEX. Locked is the part of the order
+// still locked in the account of the owner.
+type ExportedOrder struct {
+	ID            string         `json:"id"`
+	Owner         sdk.AccAddress `json:"owner"`
+	Pair          string         `json:"pair"`
+	Side          string         `json:"side"`
+	Price         int64          `json:"price"`
+	Quantity      int64          `json:"quantity"`
+	CumQty        int64          `json:"cum_qty"`
+	Locked        sdk.Coin       `json:"locked"`
+	CreatedHeight int64          `json:"created_height"`
+}
+
+// LockedMismatch is an account whose locked coins differ from the coins locked
+// by its open orders.
+type LockedMismatch struct {
+	Owner         sdk.AccAddress `json:"owner"`
+	OrdersLocked  sdk.Coins      `json:"orders_locked"`
+	AccountLocked sdk.Coins      `json:"account_locked"`
+}
+
+// ExportedOrders are the open orders of the DEX at the block height.
+type ExportedOrders struct {
+	ChainID     string            `json:"chain_id"`
+	BlockHeight int64             `json:"block_height"`
+	Orders      []*ExportedOrder  `json:"orders"`
+	Mismatches  []*LockedMismatch `json:"mismatches"`
+}
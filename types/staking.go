@@ -0,0 +1,63 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	staketypes "github.com/cosmos/cosmos-sdk/x/stake/types"
+)
+
+// DistributionBalance is the balance of the distribution address of a
+// validator, the fees and rewards waiting for the next distribution.
+type DistributionBalance struct {
+	Validator sdk.ValAddress `json:"validator"`
+	Address   sdk.AccAddress `json:"address"`
+	Coins     sdk.Coins      `json:"coins"`
+}
+
+// StakingTotals are the totals of the staking state of a chain, the amounts
+// are in the bond denom.
+type StakingTotals struct {
+	Validators           int64     `json:"validators"`
+	Delegations          int64     `json:"delegations"`
+	UnbondingDelegations int64     `json:"unbonding_delegations"`
+	Redelegations        int64     `json:"redelegations"`
+	Tokens               int64     `json:"tokens"`
+	Unbonding            int64     `json:"unbonding"`
+	Redelegating         int64     `json:"redelegating"`
+	PendingRewards       int64     `json:"pending_rewards"`
+	DistributionBalance  sdk.Coins `json:"distribution_balance"`
+}
+
+// ExportedStakingChain is the staking state of the Beacon Chain, or of a side
+// chain when SideChainID is set.
+type ExportedStakingChain struct {
+	SideChainID          string                      `json:"side_chain_id,omitempty"`
+	Params               stake.Params                `json:"params"`
+	Pool                 stake.Pool                  `json:"pool"`
+	Validators           []stake.Validator           `json:"validators"`
+	Delegations          []stake.Delegation          `json:"delegations"`
+	UnbondingDelegations []stake.UnbondingDelegation `json:"unbonding_delegations"`
+	Redelegations        []stake.Redelegation        `json:"redelegations"`
+	PendingRewards       []staketypes.Reward         `json:"pending_rewards"`
+	DistributionBalances []*DistributionBalance      `json:"distribution_balances"`
+	Totals               StakingTotals               `json:"totals"`
+}
+
+// StakingEscrow reconciles the balance of the delegation escrow with the tokens
+// of the validators and the unbonding delegations of every chain.
+type StakingEscrow struct {
+	Address    sdk.AccAddress `json:"address"`
+	Balance    int64          `json:"balance"`
+	Tokens     int64          `json:"tokens"`
+	Unbonding  int64          `json:"unbonding"`
+	Difference int64          `json:"difference"`
+}
+
+// ExportedStaking is the staking state at the block height.
+type ExportedStaking struct {
+	ChainID     string                  `json:"chain_id"`
+	BlockHeight int64                   `json:"block_height"`
+	BondDenom   string                  `json:"bond_denom"`
+	Chains      []*ExportedStakingChain `json:"chains"`
+	Escrow      StakingEscrow           `json:"escrow"`
+}
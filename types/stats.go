@@ -0,0 +1,39 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Holder is the balance of an address in a denom.
+type Holder struct {
+	Address sdk.AccAddress `json:"address"`
+	Amount  int64          `json:"amount"`
+}
+
+// Percentile is the balance of the holders at percentile P, by nearest rank.
+type Percentile struct {
+	P      float64 `json:"p"`
+	Amount int64   `json:"amount"`
+}
+
+// DenomStats is the distribution of a denom among its holders. TopShares are
+// the shares of the total held by the top 1% and 10% of the holders, Gini is
+// the Gini coefficient of the balances of the holders.
+type DenomStats struct {
+	Denom       string        `json:"denom"`
+	Holders     int64         `json:"holders"`
+	Total       int64         `json:"total"`
+	Top         []*Holder     `json:"top"`
+	Percentiles []*Percentile `json:"percentiles"`
+	Top1Share   float64       `json:"top_1_share"`
+	Top10Share  float64       `json:"top_10_share"`
+	Gini        float64       `json:"gini"`
+}
+
+// HolderStats are the distributions of the denoms of the exported accounts,
+// EmptyAccounts counts the accounts without a nonzero coin.
+type HolderStats struct {
+	Accounts      int64         `json:"accounts"`
+	EmptyAccounts int64         `json:"empty_accounts"`
+	Denoms        []*DenomStats `json:"denoms"`
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"encoding/json"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// StoreInventory is a KV store mounted in the multistore. RootHash is the root
+// hash of its IAVL tree, LeafValue the value of the store in the simple merkle
+// tree of the commit, empty when the store is not committed. Bytes is the size
+// of the keys and values of the store without the IAVL nodes.
+type StoreInventory struct {
+	Name      string       `json:"name"`
+	Version   int64        `json:"version"`
+	RootHash  cmn.HexBytes `json:"root_hash"`
+	LeafValue cmn.HexBytes `json:"leaf_value,omitempty"`
+	Committed bool         `json:"committed"`
+	Keys      int64        `json:"keys"`
+	Bytes     int64        `json:"bytes"`
+}
+
+// ExportedStores are the stores of the multistore at the block height. The
+// commit hash is the simple merkle root of the leaf values of the committed
+// stores keyed by their names, ComputedHash is the root computed from the
+// stores.
+type ExportedStores struct {
+	ChainID      string            `json:"chain_id"`
+	BlockHeight  int64             `json:"block_height"`
+	CommitID     sdk.CommitID      `json:"commit_id"`
+	ComputedHash cmn.HexBytes      `json:"computed_hash"`
+	Stores       []*StoreInventory `json:"stores"`
+}
+
+// InspectedEntry is a key and value of a store. Type is the type the value is
+// decoded as, Value its amino JSON, and Hex the raw value when its type is not
+// known.
+type InspectedEntry struct {
+	Key   cmn.HexBytes    `json:"key"`
+	Type  string          `json:"type,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Hex   cmn.HexBytes    `json:"hex,omitempty"`
+}
+
+// InspectedStore are the entries of a store under a key prefix, More is set
+// when the entries were limited.
+type InspectedStore struct {
+	Store   string            `json:"store"`
+	Prefix  cmn.HexBytes      `json:"prefix"`
+	Entries []*InspectedEntry `json:"entries"`
+	More    bool              `json:"more"`
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ExportedMsg is a message of an exported transaction. Kind is transfer,
+// order, token or other, Value is the amino JSON encoding of the message.
+type ExportedMsg struct {
+	Kind  string          `json:"kind"`
+	Route string          `json:"route"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ExportedTx is a transaction of the Tendermint tx index, Code is the code of
+// its result, 0 when the transaction succeeded.
+type ExportedTx struct {
+	Hash     string         `json:"hash"`
+	Height   int64          `json:"height"`
+	Index    uint32         `json:"index"`
+	Time     time.Time      `json:"time"`
+	Code     uint32         `json:"code"`
+	Log      string         `json:"log,omitempty"`
+	Memo     string         `json:"memo,omitempty"`
+	Messages []*ExportedMsg `json:"messages"`
+}
+
+// TxHistory is the transactions whose tags involve the address.
+type TxHistory struct {
+	Address string        `json:"address"`
+	Txs     []*ExportedTx `json:"txs"`
+}
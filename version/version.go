@@ -0,0 +1,5 @@
+// Package version holds the version of the dump tool.
+package version
+
+// Version is the version of the dump tool, it is set at build time by the Makefile.
+var Version = "dev"